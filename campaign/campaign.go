@@ -0,0 +1,352 @@
+/*
+ * Copyright (c) 2020 Juan Medina.
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package campaign
+
+import (
+	"fmt"
+	"github.com/juan-medina/goecs"
+	"github.com/juan-medina/gosge"
+	"github.com/juan-medina/gosge/components/color"
+	"github.com/juan-medina/gosge/components/geometry"
+	"github.com/juan-medina/gosge/components/shapes"
+	"github.com/juan-medina/gosge/components/ui"
+	"github.com/juan-medina/gosge/events"
+	"github.com/juan-medina/mesh2prod/game/constants"
+	"github.com/juan-medina/mesh2prod/game/level"
+	"github.com/juan-medina/mesh2prod/game/winning"
+	"github.com/juan-medina/mesh2prod/scene"
+	"reflect"
+)
+
+const (
+	clickSound        = "resources/audio/click.wav"      // button click sound
+	shadowExtraWidth  = 3                                // the x offset for the buttons shadow
+	shadowExtraHeight = 3                                // the y offset for the buttons shadow
+	font              = "resources/fonts/go_regular.fnt" // our message text font
+	fontBigSize       = 60                               // big text font size
+	fontSmallSize     = 30                               // small text font size
+	controlBorder     = 2                                // controls border thickness
+	levelWidth        = 250                              // width of a level on the map
+	levelHeight       = 90                               // height of a level on the map
+	levelGap          = 120                              // gap between levels on the map
+	music             = "resources/music/menu/Of Far Different Nature - Adventure Begins (CC-BY).ogg"
+)
+
+var (
+	gEng *gosge.Engine
+)
+
+// Stage the campaign map
+func Stage(eng *gosge.Engine) error {
+	var err error
+	gEng = eng
+	eng.DisableExitKey()
+
+	// preload music
+	if err = eng.LoadMusic(music); err != nil {
+		return err
+	}
+
+	// pre-load click sound
+	if err = eng.LoadSound(clickSound); err != nil {
+		return err
+	}
+
+	// pre-load font
+	if err = eng.LoadFont(font); err != nil {
+		return err
+	}
+
+	// design resolution is how our game is designed
+	dr := geometry.Size{Width: 1920, Height: 1080}
+
+	// game scale from the real screen size to our design resolution
+	gs := eng.GetScreenSize().CalculateScale(dr)
+
+	// get the ECS world
+	world := eng.World()
+
+	// add the sky
+	scene.Sky(world, dr, gs)
+
+	// create the map
+	createMap(eng, world, dr, gs)
+
+	// listen to level selection
+	world.AddListener(selectLevelListener, selectLevelEventType)
+
+	// add the navigation
+	scene.Navigation(eng, back)
+
+	world.Signal(events.PlayMusicEvent{Name: music, Volume: 0.5})
+
+	return nil
+}
+
+func createMap(eng *gosge.Engine, world *goecs.World, dr geometry.Size, gs geometry.Scale) {
+	current := level.Current(eng.GetSettings())
+
+	// add the title
+	world.AddEntity(
+		ui.Text{
+			String:     fmt.Sprintf("%s cloud", constants.CloudNames[current.Cloud]),
+			Size:       fontBigSize * gs.Max,
+			Font:       font,
+			VAlignment: ui.MiddleVAlignment,
+			HAlignment: ui.CenterHAlignment,
+		},
+		geometry.Point{
+			X: dr.Width * gs.Point.X * 0.5,
+			Y: dr.Height * gs.Point.Y * 0.25,
+		},
+		color.White,
+	)
+
+	total := float32(len(level.Environments))
+	mapWidth := (levelWidth * total) + (levelGap * (total - 1))
+
+	levelPos := geometry.Point{
+		X: (dr.Width * gs.Point.X * 0.5) - (mapWidth * gs.Max * 0.5),
+		Y: (dr.Height * gs.Point.Y * 0.5) - (levelHeight * gs.Max * 0.5),
+	}
+
+	var focus *goecs.Entity
+
+	for _, env := range level.Environments {
+		lvl := level.Get(current.Cloud, env)
+		unlocked := lvl.Unlocked(eng.GetSettings())
+
+		// connect with the next level
+		if next, ok := lvl.Next(); ok {
+			lineColor := color.Gray
+			if next.Unlocked(eng.GetSettings()) {
+				lineColor = color.White
+			}
+			world.AddEntity(
+				shapes.Line{
+					To: geometry.Point{
+						X: levelPos.X + ((levelWidth + levelGap) * gs.Max),
+						Y: levelPos.Y + (levelHeight * 0.5 * gs.Max),
+					},
+					Thickness: 5 * gs.Max,
+				},
+				geometry.Point{
+					X: levelPos.X + (levelWidth * gs.Max),
+					Y: levelPos.Y + (levelHeight * 0.5 * gs.Max),
+				},
+				lineColor,
+			)
+		}
+
+		if unlocked {
+			ent := addLevelButton(world, gs, lvl, levelPos)
+			// we focus on the current level, or the first one
+			if focus == nil || lvl.Environment == current.Environment {
+				focus = ent
+			}
+		} else {
+			addLockedLevel(world, gs, lvl, levelPos)
+		}
+
+		// add the level status
+		world.AddEntity(
+			ui.Text{
+				String:     levelStatus(eng, lvl, unlocked),
+				Size:       fontSmallSize * gs.Max,
+				Font:       font,
+				VAlignment: ui.TopVAlignment,
+				HAlignment: ui.CenterHAlignment,
+			},
+			geometry.Point{
+				X: levelPos.X + (levelWidth * 0.5 * gs.Max),
+				Y: levelPos.Y + ((levelHeight + 10) * gs.Max),
+			},
+			color.White,
+		)
+
+		levelPos.X += (levelWidth + levelGap) * gs.Max
+	}
+
+	backSize := geometry.Size{
+		Width:  200,
+		Height: 50,
+	}
+
+	// add the back button
+	world.AddEntity(
+		ui.FlatButton{
+			Shadow: geometry.Size{Width: shadowExtraWidth * gs.Max, Height: shadowExtraHeight * gs.Max},
+			Event: events.DelaySignal{
+				Signal: events.ChangeGameStage{Stage: "menu"},
+				Time:   0.25,
+			},
+			Sound:  clickSound,
+			Volume: 1,
+		},
+		geometry.Point{
+			X: (dr.Width * gs.Point.X * 0.5) - (backSize.Width * 0.5 * gs.Max),
+			Y: (dr.Height * gs.Point.Y * 0.75),
+		},
+		shapes.Box{
+			Size:      backSize,
+			Scale:     gs.Max,
+			Thickness: int32(controlBorder * gs.Max),
+		},
+		ui.Text{
+			String:     "Back",
+			Size:       fontSmallSize * gs.Max,
+			Font:       font,
+			VAlignment: ui.MiddleVAlignment,
+			HAlignment: ui.CenterHAlignment,
+		},
+		ui.ButtonColor{
+			Gradient: color.Gradient{
+				From: color.Red,
+				To:   color.DarkPurple,
+			},
+			Border: color.DarkBlue,
+			Text:   color.SkyBlue,
+		},
+	)
+
+	world.Signal(events.FocusOnControlEvent{Control: focus})
+}
+
+func addLevelButton(world *goecs.World, gs geometry.Scale, lvl level.Level, pos geometry.Point) *goecs.Entity {
+	return world.AddEntity(
+		ui.FlatButton{
+			Shadow: geometry.Size{Width: shadowExtraWidth * gs.Max, Height: shadowExtraHeight * gs.Max},
+			Event: events.DelaySignal{
+				Signal: selectLevelEvent{lvl: lvl},
+				Time:   0.25,
+			},
+			Sound:  clickSound,
+			Volume: 1,
+		},
+		pos,
+		shapes.Box{
+			Size: geometry.Size{
+				Width:  levelWidth,
+				Height: levelHeight,
+			},
+			Scale:     gs.Max,
+			Thickness: int32(controlBorder * gs.Max),
+		},
+		ui.Text{
+			String:     level.EnvironmentNames[lvl.Environment],
+			Size:       fontBigSize * 0.75 * gs.Max,
+			Font:       font,
+			VAlignment: ui.MiddleVAlignment,
+			HAlignment: ui.CenterHAlignment,
+		},
+		ui.ButtonColor{
+			Gradient: color.Gradient{
+				From: color.Red,
+				To:   color.DarkPurple,
+			},
+			Border: color.DarkBlue,
+			Text:   color.SkyBlue,
+		},
+	)
+}
+
+func addLockedLevel(world *goecs.World, gs geometry.Scale, lvl level.Level, pos geometry.Point) {
+	size := geometry.Size{
+		Width:  levelWidth,
+		Height: levelHeight,
+	}
+
+	world.AddEntity(
+		shapes.SolidBox{
+			Size:  size,
+			Scale: gs.Max,
+		},
+		pos,
+		color.Gray.Alpha(180),
+	)
+
+	world.AddEntity(
+		shapes.Box{
+			Size:      size,
+			Scale:     gs.Max,
+			Thickness: int32(controlBorder * gs.Max),
+		},
+		pos,
+		color.DarkGray,
+	)
+
+	world.AddEntity(
+		ui.Text{
+			String:     level.EnvironmentNames[lvl.Environment],
+			Size:       fontBigSize * 0.75 * gs.Max,
+			Font:       font,
+			VAlignment: ui.MiddleVAlignment,
+			HAlignment: ui.CenterHAlignment,
+		},
+		geometry.Point{
+			X: pos.X + (size.Width * 0.5 * gs.Max),
+			Y: pos.Y + (size.Height * 0.5 * gs.Max),
+		},
+		color.DarkGray,
+	)
+}
+
+// get the text that describe the status of a level
+func levelStatus(eng *gosge.Engine, lvl level.Level, unlocked bool) string {
+	if !unlocked {
+		return "locked"
+	}
+
+	setting := fmt.Sprintf(constants.BestGradeConfig, lvl.Key())
+	best := winning.Grade(eng.GetSettings().GetIn32(setting, int32(winning.NoGrade)))
+
+	if best == winning.NoGrade {
+		return "no SLO yet"
+	}
+
+	return "SLO " + winning.SLOs[lvl.Cloud][best].Name
+}
+
+func selectLevelListener(world *goecs.World, signal interface{}, _ float32) error {
+	switch e := signal.(type) {
+	case selectLevelEvent:
+		level.Select(gEng.GetSettings(), e.lvl)
+		world.Signal(events.ChangeGameStage{Stage: "game"})
+	}
+	return nil
+}
+
+// go back to the menu
+func back(world *goecs.World) {
+	world.Signal(events.PlaySoundEvent{Name: clickSound, Volume: 1})
+	world.Signal(events.DelaySignal{
+		Signal: events.ChangeGameStage{Stage: "menu"},
+		Time:   0.25,
+	})
+}
+
+type selectLevelEvent struct {
+	lvl level.Level
+}
+
+var selectLevelEventType = reflect.TypeOf(selectLevelEvent{})
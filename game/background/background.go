@@ -23,7 +23,6 @@
 package background
 
 import (
-	"fmt"
 	"github.com/juan-medina/goecs"
 	"github.com/juan-medina/gosge"
 	"github.com/juan-medina/gosge/components/color"
@@ -31,23 +30,19 @@ import (
 	"github.com/juan-medina/gosge/components/geometry"
 	"github.com/juan-medina/gosge/components/shapes"
 	"github.com/juan-medina/gosge/components/sprite"
-	"github.com/juan-medina/mesh2prod/game/constants"
+	"github.com/juan-medina/mesh2prod/game/daytime"
 	"github.com/juan-medina/mesh2prod/game/movement"
+	"github.com/juan-medina/mesh2prod/game/theme"
 	"math/rand"
 	"reflect"
 )
 
-const (
-	cloudLayers    = 8   // number of cloud layers
-	numClouds      = 10  // number of clouds
-	minCloudSpeed  = 200 // Min cloud speed
-	cloudDiffSpeed = 60  // difference of speed per Layer
-)
-
 type bgSystem struct {
-	gs  geometry.Scale
-	dr  geometry.Size
-	eng *gosge.Engine
+	gs    geometry.Scale
+	dr    geometry.Size
+	eng   *gosge.Engine
+	theme theme.Theme   // theme for the sky and clouds
+	sky   *goecs.Entity // sky gradient
 }
 
 // add the background
@@ -60,7 +55,7 @@ func (bs *bgSystem) load(eng *gosge.Engine) error {
 	world := eng.World()
 
 	// add a gradient background
-	world.AddEntity(
+	bs.sky = world.AddEntity(
 		shapes.SolidBox{
 			Size: geometry.Size{
 				Width:  bs.dr.Width,
@@ -69,20 +64,15 @@ func (bs *bgSystem) load(eng *gosge.Engine) error {
 			Scale: bs.gs.Max,
 		},
 		geometry.Point{},
-		color.Gradient{
-			From:      color.White,
-			To:        color.SkyBlue,
-			Direction: color.GradientVertical,
-		},
+		bs.theme.Sky.Gradient(),
 	)
 	var ent *goecs.Entity
 
 	// for each layer
-	for ln := 0; ln < cloudLayers; ln++ {
-		alpha := 180 - uint8((float32(ln)/cloudLayers)*100)
-		cloudAlpha := color.White.Alpha(alpha)
+	for ln := 0; ln < bs.theme.Layers; ln++ {
+		cloudAlpha := bs.cloudColor(ln)
 		// for each number off cloud
-		for cn := 0; cn < numClouds; cn++ {
+		for cn := 0; cn < bs.theme.PerLayer; cn++ {
 			// add a cloud
 			ent = world.AddEntity(
 				cloudAlpha,
@@ -108,31 +98,54 @@ func (bs *bgSystem) load(eng *gosge.Engine) error {
 	// add the reset system
 	world.AddSystem(bs.resetSystem)
 
+	// listen to the time of day
+	world.AddListener(bs.daytimeListener, daytime.ChangeEventType)
+
+	return nil
+}
+
+// the clouds color for a layer, closer layers are more opaque
+func (bs bgSystem) cloudColor(ln int) color.Solid {
+	alpha := 180 - uint8((float32(ln)/float32(bs.theme.Layers))*100)
+	return bs.theme.CloudTint.Alpha(alpha)
+}
+
+// change the sky and the clouds with the time of day
+func (bs *bgSystem) daytimeListener(world *goecs.World, signal interface{}, _ float32) error {
+	switch e := signal.(type) {
+	case daytime.ChangeEvent:
+		bs.sky.Set(e.Look.Sky(bs.theme.Sky).Gradient())
+		for it := world.Iterator(ResetType, effects.TYPE.Layer); it != nil; it = it.Next() {
+			ent := it.Value()
+			ln := int(effects.Get.Layer(ent).Depth - 1)
+			ent.Set(e.Look.Cloud(bs.cloudColor(ln)))
+		}
+	}
 	return nil
 }
 
 func (bs *bgSystem) resetCloud(ent *goecs.Entity, from float32) error {
 	// get a random sprite
-	spn := rand.Intn(3) + 1
-	sf := fmt.Sprintf("cloud%d.PNG", spn)
+	sf := bs.theme.Clouds[rand.Intn(len(bs.theme.Clouds))]
 
 	// get the layer number
 	layer := effects.Get.Layer(ent)
 	ln := layer.Depth - 1
+	layers := float32(bs.theme.Layers)
 
 	// set the scale according to the layer
-	scale := 1.5 - (ln/cloudLayers)*1.5
+	scale := 1.5 - (ln/layers)*1.5
 
 	// sprite component
 	spr := sprite.Sprite{
-		Sheet: constants.SpriteSheet,
+		Sheet: bs.theme.Sheet,
 		Name:  sf,
 		Scale: bs.gs.Max * scale,
 		FlipX: rand.Intn(2) == 0, // random flipped horizontally
 	}
 
 	// speed base on the layer
-	speed := -(minCloudSpeed + (cloudDiffSpeed * (cloudLayers - ln)))
+	speed := -(bs.theme.MinSpeed + (bs.theme.LayerSpeed * (layers - ln)))
 
 	// movement component
 	mov := movement.Movement{
@@ -164,7 +177,7 @@ func (bs *bgSystem) resetCloud(ent *goecs.Entity, from float32) error {
 	var err error
 
 	//get sprite size
-	if size, err = bs.eng.GetSpriteSize(constants.SpriteSheet, sf); err != nil {
+	if size, err = bs.eng.GetSpriteSize(bs.theme.Sheet, sf); err != nil {
 		return err
 	}
 
@@ -176,6 +189,7 @@ func (bs *bgSystem) resetCloud(ent *goecs.Entity, from float32) error {
 	ent.Set(mov)
 	ent.Set(pos)
 	ent.Set(rst)
+	ent.Set(movement.Scroll{})
 
 	return nil
 }
@@ -217,10 +231,11 @@ type Reset struct {
 var ResetType = reflect.TypeOf(Reset{})
 
 // System creates the background system
-func System(engine *gosge.Engine, gs geometry.Scale, dr geometry.Size) error {
+func System(engine *gosge.Engine, gs geometry.Scale, dr geometry.Size, theme theme.Theme) error {
 	bs := bgSystem{
-		gs: gs,
-		dr: dr,
+		gs:    gs,
+		dr:    dr,
+		theme: theme,
 	}
 	return bs.load(engine)
 }
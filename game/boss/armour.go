@@ -0,0 +1,136 @@
+/*
+ * Copyright (c) 2020 Juan Medina.
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package boss
+
+import "github.com/juan-medina/mesh2prod/game/grid"
+
+type cellState int
+
+const (
+	empty = cellState(iota)
+	plate
+	cleared
+)
+
+// Cell is a position in the Armour grid
+type Cell struct {
+	C, R int
+}
+
+// Armour is the Monolith armour grid, with weak points under its plates
+type Armour struct {
+	Cols, Rows int           // size of the grid
+	cells      [][]cellState // state of each cell
+	weak       [][]int       // health of the weak point in each cell, 0 if there is none
+}
+
+// NewArmour creates an Armour from a layout, one string per row where '#' is a plate,
+// 'O' is a weak point under a plate, and anything else is empty
+func NewArmour(layout []string, weakHealth int) *Armour {
+	a := &Armour{Rows: len(layout)}
+	for _, row := range layout {
+		if len(row) > a.Cols {
+			a.Cols = len(row)
+		}
+	}
+
+	a.cells = make([][]cellState, a.Cols)
+	a.weak = make([][]int, a.Cols)
+	for c := 0; c < a.Cols; c++ {
+		a.cells[c] = make([]cellState, a.Rows)
+		a.weak[c] = make([]int, a.Rows)
+	}
+
+	for r, row := range layout {
+		for c, ch := range row {
+			switch ch {
+			case '#':
+				a.cells[c][r] = plate
+			case 'O':
+				a.cells[c][r] = plate
+				a.weak[c][r] = weakHealth
+			}
+		}
+	}
+
+	return a
+}
+
+// Plate returns if there is a plate in a cell
+func (a Armour) Plate(c, r int) bool {
+	return a.inside(c, r) && a.cells[c][r] == plate
+}
+
+// Exposed returns if there is a weak point in a cell that has been exposed
+func (a Armour) Exposed(c, r int) bool {
+	return a.inside(c, r) && a.cells[c][r] == cleared && a.weak[c][r] > 0
+}
+
+// Remaining is the total health left in the weak points
+func (a Armour) Remaining() int {
+	total := 0
+	for c := 0; c < a.Cols; c++ {
+		for r := 0; r < a.Rows; r++ {
+			total += a.weak[c][r]
+		}
+	}
+	return total
+}
+
+// Hit a plate, that place a new plate on its left, returns if it has been placed and the cells that clear
+func (a *Armour) Hit(c, r int) (bool, []Cell) {
+	pc := c - 1
+	if !a.Plate(c, r) || !a.inside(pc, r) || a.Plate(pc, r) || a.Exposed(pc, r) {
+		return false, nil
+	}
+
+	a.cells[pc][r] = plate
+
+	result := make([]Cell, 0)
+	for _, ar := range grid.FindAreas(a.Cols, a.Rows, a.Plate, pc, r) {
+		for ac := ar.FromC; ac <= ar.ToC; ac++ {
+			for rr := ar.FromR; rr <= ar.ToR; rr++ {
+				if a.cells[ac][rr] == plate {
+					a.cells[ac][rr] = cleared
+					result = append(result, Cell{C: ac, R: rr})
+				}
+			}
+		}
+	}
+
+	return true, result
+}
+
+// Strike an exposed weak point, returns if it has been destroyed
+func (a *Armour) Strike(c, r int) bool {
+	if !a.Exposed(c, r) {
+		return false
+	}
+	a.weak[c][r]--
+	return a.weak[c][r] == 0
+}
+
+// is a cell inside the grid
+func (a Armour) inside(c, r int) bool {
+	return c >= 0 && c < a.Cols && r >= 0 && r < a.Rows
+}
@@ -0,0 +1,146 @@
+/*
+ * Copyright (c) 2020 Juan Medina.
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package boss
+
+import (
+	"fmt"
+	"testing"
+)
+
+var testLayout = []string{
+	"  ######",
+	"   #####",
+	"   #O###",
+	"   #####",
+	"  ######",
+}
+
+func TestNewArmour(t *testing.T) {
+	a := NewArmour(testLayout, 3)
+
+	if a.Cols != 8 || a.Rows != 5 {
+		t.Fatalf("size error, got %vx%v, expect %vx%v", a.Cols, a.Rows, 8, 5)
+	}
+
+	type tc struct {
+		c, r   int
+		expect bool
+	}
+
+	cases := []tc{
+		{c: 0, r: 0, expect: false},
+		{c: 2, r: 0, expect: true},
+		{c: 2, r: 1, expect: false},
+		{c: 4, r: 2, expect: true},
+		{c: 8, r: 0, expect: false},
+		{c: -1, r: 0, expect: false},
+	}
+
+	for i, v := range cases {
+		t.Run(fmt.Sprintf("case %d", i+1), func(t *testing.T) {
+			if got := a.Plate(v.c, v.r); got != v.expect {
+				t.Fatalf("plate error, got %v, expect %v", got, v.expect)
+			}
+		})
+	}
+
+	if got := a.Remaining(); got != 3 {
+		t.Fatalf("remaining error, got %v, expect %v", got, 3)
+	}
+}
+
+func TestArmour_Hit(t *testing.T) {
+	a := NewArmour(testLayout, 3)
+
+	type tc struct {
+		c, r    int
+		placed  bool
+		cleared int
+	}
+
+	cases := []tc{
+		// there is no plate to hit
+		{c: 0, r: 0, placed: false, cleared: 0},
+		{c: 1, r: 1, placed: false, cleared: 0},
+		// close the top rows, clearing them
+		{c: 3, r: 1, placed: true, cleared: 12},
+		// a cleared plate could not be hit
+		{c: 3, r: 1, placed: false, cleared: 0},
+		{c: 3, r: 2, placed: true, cleared: 0},
+		// the left is already a plate
+		{c: 3, r: 2, placed: false, cleared: 0},
+		// close the bottom rows, clearing them with the weak point
+		{c: 3, r: 3, placed: true, cleared: 18},
+	}
+
+	for i, v := range cases {
+		t.Run(fmt.Sprintf("case %d", i+1), func(t *testing.T) {
+			placed, cleared := a.Hit(v.c, v.r)
+			if placed != v.placed {
+				t.Fatalf("placed error, got %v, expect %v", placed, v.placed)
+			}
+			if len(cleared) != v.cleared {
+				t.Fatalf("cleared error, got %v, expect %v", len(cleared), v.cleared)
+			}
+		})
+	}
+
+	if !a.Exposed(4, 2) {
+		t.Fatalf("exposed error, got %v, expect %v", false, true)
+	}
+}
+
+func TestArmour_Strike(t *testing.T) {
+	a := NewArmour(testLayout, 2)
+
+	// is not exposed yet
+	if got := a.Strike(4, 2); got {
+		t.Fatalf("strike error, got %v, expect %v", got, false)
+	}
+
+	a.Hit(3, 1)
+	a.Hit(3, 2)
+	a.Hit(3, 3)
+
+	type tc struct {
+		remaining int
+		expect    bool
+	}
+
+	cases := []tc{
+		{remaining: 1, expect: false},
+		{remaining: 0, expect: true},
+		{remaining: 0, expect: false},
+	}
+
+	for i, v := range cases {
+		t.Run(fmt.Sprintf("case %d", i+1), func(t *testing.T) {
+			if got := a.Strike(4, 2); got != v.expect {
+				t.Fatalf("strike error, got %v, expect %v", got, v.expect)
+			}
+			if got := a.Remaining(); got != v.remaining {
+				t.Fatalf("remaining error, got %v, expect %v", got, v.remaining)
+			}
+		})
+	}
+}
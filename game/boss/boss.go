@@ -0,0 +1,516 @@
+/*
+ * Copyright (c) 2020 Juan Medina.
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package boss
+
+import (
+	"github.com/juan-medina/goecs"
+	"github.com/juan-medina/gosge"
+	"github.com/juan-medina/gosge/components/audio"
+	"github.com/juan-medina/gosge/components/color"
+	"github.com/juan-medina/gosge/components/effects"
+	"github.com/juan-medina/gosge/components/geometry"
+	"github.com/juan-medina/gosge/components/shapes"
+	"github.com/juan-medina/gosge/components/sprite"
+	"github.com/juan-medina/gosge/components/ui"
+	"github.com/juan-medina/gosge/events"
+	"github.com/juan-medina/mesh2prod/game/collision"
+	"github.com/juan-medina/mesh2prod/game/component"
+	"github.com/juan-medina/mesh2prod/game/constants"
+	"github.com/juan-medina/mesh2prod/game/enemy"
+	"github.com/juan-medina/mesh2prod/game/movement"
+	"github.com/juan-medina/mesh2prod/game/pause"
+	"github.com/juan-medina/mesh2prod/game/score"
+	"github.com/juan-medina/mesh2prod/game/winning"
+	"math/rand"
+	"strings"
+)
+
+// logic constants
+const (
+	plateSprite = "box.png"                        // armour plate sprite
+	plateScale  = 0.75                             // armour plate scale
+	rightGap    = 60                               // gap between the Monolith and the right of the screen
+	weakHealth  = 3                                // hits to destroy an exposed weak point
+	weakCoins   = 5                                // BlockCoins, as blocks cleared, for a weak point
+	bulletSpeed = 350                              // Monolith bullets speed
+	barWidth    = 600                              // health bar width
+	barHeight   = 30                               // health bar height
+	barY        = 60                               // health bar Y
+	font        = "resources/fonts/go_regular.fnt" // health bar text font
+	fontSize    = 30                               // health bar text font size
+	hitSound    = "resources/audio/hit.wav"        // armour hit sound
+	popSound    = "resources/audio/pop.wav"        // weak point destroyed sound
+	winSound    = "resources/audio/win.wav"        // Monolith defeated sound
+	// Monolith music cue
+	bossMusic = "resources/music/boss/Of Far Different Nature - Vengeance Electro [v2] (CC-BY 4.0).ogg"
+)
+
+// phase of the Monolith
+type phase struct {
+	layout    []string    // layout of the armour
+	color     color.Solid // color of the armour plates
+	fireDelay float32     // seconds between volleys
+	shots     int         // bullets in each volley
+}
+
+var (
+	phases = []phase{
+		{
+			layout: []string{
+				"  ######",
+				"   #####",
+				"   #O###",
+				"   #####",
+				"  ######",
+				"  ######",
+				"   #####",
+				"   ##O##",
+				"   #####",
+				"  ######",
+				"   #####",
+				"   #####",
+			},
+			color:     color.LightGray,
+			fireDelay: 2,
+			shots:     1,
+		},
+		{
+			layout: []string{
+				"  ######",
+				"  #O####",
+				"   #####",
+				"  ######",
+				"   ###O#",
+				"  ######",
+				"  ######",
+				"   #O###",
+				"  ######",
+				"   #####",
+				"  ####O#",
+				"  ######",
+			},
+			color:     color.Gray,
+			fireDelay: 1.5,
+			shots:     2,
+		},
+		{
+			layout: []string{
+				"    ####",
+				"  ##O###",
+				"   #####",
+				"  ###O##",
+				"    ####",
+				"  #O####",
+				"   #####",
+				"  ####O#",
+				"    ####",
+				"  ##O###",
+				"   #####",
+				"  ######",
+			},
+			color:     color.DarkGray,
+			fireDelay: 1,
+			shots:     3,
+		},
+	}
+
+	healthColors = ui.ProgressBarColor{
+		Gradient: color.Gradient{
+			From:      color.Red,
+			To:        color.Maroon,
+			Direction: color.GradientHorizontal,
+		},
+		Border: color.White,
+		Empty:  color.Black,
+	} // health bar colors
+)
+
+type bossSystem struct {
+	pause.State
+	gs        geometry.Scale
+	dr        geometry.Size
+	plateSize geometry.Size     // plate sprite size
+	armour    *Armour           // current phase armour
+	plates    [][]*goecs.Entity // armour plates sprites
+	weak      [][]*goecs.Entity // exposed weak points sprites
+	phase     int               // current phase
+	phases    int               // number of phases in this level
+	health    int               // total health, of all phases
+	healthBar *goecs.Entity     // health bar
+	label     *goecs.Entity     // health bar label
+	fireIn    float32           // time to the next volley
+	active    bool              // is the Monolith active
+	end       bool
+}
+
+// load the system
+func (bs *bossSystem) load(eng *gosge.Engine) error {
+	var err error
+
+	// pre-load font
+	if err = eng.LoadFont(font); err != nil {
+		return err
+	}
+
+	// pre-load the music cue
+	if err = eng.LoadMusic(bossMusic); err != nil {
+		return err
+	}
+
+	// pre-load sounds
+	for _, sound := range []string{hitSound, popSound, winSound} {
+		if err = eng.LoadSound(sound); err != nil {
+			return err
+		}
+	}
+
+	// get the plate size
+	if bs.plateSize, err = eng.GetSpriteSize(constants.SpriteSheet, plateSprite); err != nil {
+		return err
+	}
+
+	world := eng.World()
+
+	// listen to the boss stage
+	world.AddListener(bs.stageListener, winning.BossStageEventType)
+
+	// listen to armour hits
+	world.AddListener(bs.collisionListener, collision.BulletHitArmourEventType)
+
+	// fire volleys
+	world.AddSystem(bs.fireSystem)
+
+	// keep track of the pause
+	bs.Track(world)
+
+	// listen to level events
+	world.AddListener(bs.levelEvents, winning.LevelEndEventType)
+
+	return nil
+}
+
+// start the boss stage
+func (bs *bossSystem) stageListener(world *goecs.World, signal interface{}, _ float32) error {
+	if bs.end {
+		return nil
+	}
+	switch e := signal.(type) {
+	case winning.BossStageEvent:
+		bs.phases = e.Phases
+		if bs.phases > len(phases) {
+			bs.phases = len(phases)
+		}
+		bs.health = 0
+		for i := 0; i < bs.phases; i++ {
+			bs.health += NewArmour(phases[i].layout, weakHealth).Remaining()
+		}
+		bs.active = true
+
+		// nothing scroll until the Monolith is defeated
+		world.Signal(movement.HoldScrollEvent{Hold: true})
+
+		bs.playMusic(world)
+		bs.addHealthBar(world)
+		bs.startPhase(world, 0)
+	}
+	return nil
+}
+
+// stop the game music and play the Monolith music cue
+func (bs *bossSystem) playMusic(world *goecs.World) {
+	for it := world.Iterator(audio.TYPE.MusicState); it != nil; it = it.Next() {
+		sta := audio.Get.MusicState(it.Value())
+		if sta.PlayingState == audio.StatePlaying && !strings.Contains(sta.Name, "plane") {
+			world.Signal(events.StopMusicEvent{Name: sta.Name})
+			break
+		}
+	}
+	world.Signal(events.PlayMusicEvent{Name: bossMusic, Volume: 0.5})
+}
+
+// add the health bar, at the top center of the screen
+func (bs *bossSystem) addHealthBar(world *goecs.World) {
+	pos := geometry.Point{
+		X: (bs.dr.Width - barWidth) * 0.5 * bs.gs.Max,
+		Y: barY * bs.gs.Max,
+	}
+
+	bs.healthBar = world.AddEntity(
+		ui.ProgressBar{
+			Min:     0,
+			Max:     1,
+			Current: 1,
+			Shadow: geometry.Size{
+				Width:  5 * bs.gs.Max,
+				Height: 5 * bs.gs.Max,
+			},
+		},
+		pos,
+		shapes.Box{
+			Size: geometry.Size{
+				Width:  barWidth,
+				Height: barHeight,
+			},
+			Scale:     bs.gs.Max,
+			Thickness: int32(2 * bs.gs.Max),
+		},
+		healthColors,
+		effects.Layer{Depth: -100},
+	)
+
+	pos.X += barWidth * 0.5 * bs.gs.Max
+	pos.Y += barHeight * 0.5 * bs.gs.Max
+
+	bs.label = world.AddEntity(
+		ui.Text{
+			String:     "Monolith",
+			Size:       fontSize * bs.gs.Max,
+			Font:       font,
+			VAlignment: ui.MiddleVAlignment,
+			HAlignment: ui.CenterHAlignment,
+		},
+		pos,
+		color.White,
+		effects.Layer{Depth: -100},
+	)
+}
+
+// update the health bar with the health left in this and the next phases
+func (bs *bossSystem) updateHealthBar() {
+	remaining := bs.armour.Remaining()
+	for i := bs.phase + 1; i < bs.phases; i++ {
+		remaining += NewArmour(phases[i].layout, weakHealth).Remaining()
+	}
+	bar := ui.Get.ProgressBar(bs.healthBar)
+	bar.Current = float32(remaining) / float32(bs.health)
+	bs.healthBar.Set(bar)
+}
+
+// start a phase with a new armour
+func (bs *bossSystem) startPhase(world *goecs.World, p int) {
+	bs.removeArmour(world)
+
+	bs.phase = p
+	bs.armour = NewArmour(phases[p].layout, weakHealth)
+	bs.fireIn = phases[p].fireDelay
+
+	bs.plates = make([][]*goecs.Entity, bs.armour.Cols)
+	bs.weak = make([][]*goecs.Entity, bs.armour.Cols)
+	for c := 0; c < bs.armour.Cols; c++ {
+		bs.plates[c] = make([]*goecs.Entity, bs.armour.Rows)
+		bs.weak[c] = make([]*goecs.Entity, bs.armour.Rows)
+		for r := 0; r < bs.armour.Rows; r++ {
+			if bs.armour.Plate(c, r) {
+				bs.addPlate(world, c, r)
+			}
+		}
+	}
+
+	bs.updateHealthBar()
+}
+
+// the size of a cell in the armour
+func (bs bossSystem) cellSize() float32 {
+	return bs.plateSize.Width * plateScale * bs.gs.Max
+}
+
+// the center of a cell in the armour, the Monolith is on the right of the screen
+func (bs bossSystem) cellPos(c, r int) geometry.Point {
+	size := bs.cellSize()
+	return geometry.Point{
+		X: (bs.dr.Width-rightGap)*bs.gs.Max - float32(bs.armour.Cols-c)*size + size*0.5,
+		Y: (bs.dr.Height*bs.gs.Max-float32(bs.armour.Rows)*size)*0.5 + float32(r)*size + size*0.5,
+	}
+}
+
+// add a plate sprite
+func (bs *bossSystem) addPlate(world *goecs.World, c, r int) {
+	bs.plates[c][r] = world.AddEntity(
+		sprite.Sprite{
+			Sheet: constants.SpriteSheet,
+			Name:  plateSprite,
+			Scale: bs.gs.Max * plateScale,
+		},
+		bs.cellPos(c, r),
+		phases[bs.phase].color,
+		component.Armour{C: c, R: r},
+		effects.Layer{Depth: 0},
+	)
+}
+
+// add an exposed weak point sprite
+func (bs *bossSystem) addWeak(world *goecs.World, c, r int) {
+	bs.weak[c][r] = world.AddEntity(
+		sprite.Sprite{
+			Sheet: constants.SpriteSheet,
+			Name:  plateSprite,
+			Scale: bs.gs.Max * plateScale,
+		},
+		bs.cellPos(c, r),
+		color.Red,
+		effects.AlternateColor{
+			From:  color.Red,
+			To:    color.White,
+			Time:  0.25,
+			Delay: 0,
+		},
+		component.Armour{C: c, R: r, Weak: true},
+		effects.Layer{Depth: 0},
+	)
+}
+
+// remove all the armour sprites
+func (bs *bossSystem) removeArmour(world *goecs.World) {
+	for c := range bs.plates {
+		for r := range bs.plates[c] {
+			if bs.plates[c][r] != nil {
+				_ = world.Remove(bs.plates[c][r])
+				bs.plates[c][r] = nil
+			}
+			if bs.weak[c][r] != nil {
+				_ = world.Remove(bs.weak[c][r])
+				bs.weak[c][r] = nil
+			}
+		}
+	}
+}
+
+// hit the armour plates and the weak points
+func (bs *bossSystem) collisionListener(world *goecs.World, signal interface{}, _ float32) error {
+	if !bs.active || bs.end {
+		return nil
+	}
+	switch e := signal.(type) {
+	case collision.BulletHitArmourEvent:
+		c, r := e.Armour.C, e.Armour.R
+		if e.Armour.Weak {
+			bs.strike(world, c, r)
+			return nil
+		}
+		placed, cleared := bs.armour.Hit(c, r)
+		if !placed {
+			return nil
+		}
+		bs.addPlate(world, c-1, r)
+		for _, cell := range cleared {
+			if bs.plates[cell.C][cell.R] != nil {
+				_ = world.Remove(bs.plates[cell.C][cell.R])
+				bs.plates[cell.C][cell.R] = nil
+			}
+			if bs.armour.Exposed(cell.C, cell.R) {
+				bs.addWeak(world, cell.C, cell.R)
+			}
+		}
+		world.Signal(events.PlaySoundEvent{Name: hitSound, Volume: 1})
+	}
+	return nil
+}
+
+// strike a weak point, moving to the next phase, or defeating the Monolith, when there are none left
+func (bs *bossSystem) strike(world *goecs.World, c, r int) {
+	if !bs.armour.Strike(c, r) {
+		bs.updateHealthBar()
+		return
+	}
+
+	at := bs.cellPos(c, r)
+	if bs.weak[c][r] != nil {
+		_ = world.Remove(bs.weak[c][r])
+		bs.weak[c][r] = nil
+	}
+	world.Signal(score.PointsEvent{Total: weakCoins, At: at})
+	world.Signal(events.PlaySoundEvent{Name: popSound, Volume: 1})
+
+	if bs.armour.Remaining() > 0 {
+		bs.updateHealthBar()
+		return
+	}
+
+	if bs.phase+1 < bs.phases {
+		bs.startPhase(world, bs.phase+1)
+		return
+	}
+
+	bs.defeat(world)
+}
+
+// the Monolith is defeated, the way to production is open
+func (bs *bossSystem) defeat(world *goecs.World) {
+	bs.active = false
+	bs.removeArmour(world)
+	_ = world.Remove(bs.healthBar)
+	_ = world.Remove(bs.label)
+	world.Signal(movement.HoldScrollEvent{Hold: false})
+	world.Signal(winning.BossDefeatedEvent{})
+	world.Signal(events.PlaySoundEvent{Name: winSound, Volume: 1})
+}
+
+// fire volleys to the plane, from the exposed weak points or the armour front
+func (bs *bossSystem) fireSystem(world *goecs.World, delta float32) error {
+	if !bs.active || bs.end || bs.IsPaused() {
+		return nil
+	}
+	if bs.fireIn -= delta; bs.fireIn > 0 {
+		return nil
+	}
+	bs.fireIn = phases[bs.phase].fireDelay
+
+	from := make([]geometry.Point, 0)
+	for c := range bs.weak {
+		for r := range bs.weak[c] {
+			if bs.weak[c][r] != nil {
+				from = append(from, bs.cellPos(c, r))
+			}
+		}
+	}
+
+	for i := 0; i < phases[bs.phase].shots; i++ {
+		var pos geometry.Point
+		if len(from) > 0 {
+			pos = from[rand.Intn(len(from))]
+		} else {
+			pos = bs.cellPos(0, rand.Intn(bs.armour.Rows))
+		}
+		world.Signal(enemy.FireEvent{From: pos, Speed: bulletSpeed})
+	}
+
+	return nil
+}
+
+func (bs *bossSystem) levelEvents(_ *goecs.World, signal interface{}, _ float32) error {
+	switch signal.(type) {
+	case winning.LevelEndEvent:
+		bs.end = true
+	}
+	return nil
+}
+
+// System create the boss system
+func System(engine *gosge.Engine, gs geometry.Scale, dr geometry.Size) error {
+	bs := bossSystem{
+		gs: gs,
+		dr: dr,
+	}
+
+	return bs.load(engine)
+}
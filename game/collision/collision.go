@@ -31,10 +31,13 @@ import (
 	"github.com/juan-medina/gosge/components/sprite"
 	"github.com/juan-medina/gosge/events"
 	"github.com/juan-medina/mesh2prod/game/component"
+	"github.com/juan-medina/mesh2prod/game/movement"
+	"github.com/juan-medina/mesh2prod/game/pause"
 	"reflect"
 )
 
 type collisionSystem struct {
+	pause.State
 	eng *gosge.Engine
 }
 
@@ -46,24 +49,79 @@ func (cs *collisionSystem) load(engine *gosge.Engine) error {
 	world.AddSystem(cs.blocksCollisionsSystem)
 
 	world.AddListener(cs.removeTintsListener, RemoveTintEventType)
+
+	// keep track of the pause
+	cs.Track(world)
 	return nil
 }
 
 func (cs *collisionSystem) blocksCollisionsSystem(world *goecs.World, _ float32) error {
+	if cs.IsPaused() {
+		return nil
+	}
 	for it := world.Iterator(geometry.TYPE.Point, sprite.TYPE); it != nil; it = it.Next() {
 		ent := it.Value()
 		if ent.Contains(component.TYPE.Bullet) {
-			block := cs.checkBlocks(ent, world)
+			// bullets flying back, after a ricochet, do not hit anything
+			if ent.Contains(movement.ProjectileType) {
+				prj := ent.Get(movement.ProjectileType).(movement.Projectile)
+				if prj.Velocity.X <= 0 {
+					continue
+				}
+				if wall := cs.checkSolidHazard(ent, world); wall != nil {
+					pos, prj := prj.Ricochet(geometry.Get.Point(ent), geometry.Get.Point(wall).X)
+					ent.Set(pos)
+					ent.Set(prj)
+					continue
+				}
+			}
+			bullet := component.Get.Bullet(ent)
+			block := cs.checkBlocks(ent, world, bullet.Last)
 			if block != nil {
 				blockC := component.Get.Block(block)
-				world.Signal(BulletHitBlockEvent{Block: blockC})
-				_ = world.Remove(ent)
+				world.Signal(BulletHitBlockEvent{Block: blockC, Effect: bullet.Effect})
+				// piercing bullets continue, but do not hit the same block again
+				if bullet.Piercing {
+					bullet.Last = block
+					ent.Set(bullet)
+				} else {
+					_ = world.Remove(ent)
+				}
+				continue
+			}
+			armour := cs.checkArmour(ent, world, bullet.Last)
+			if armour != nil {
+				world.Signal(BulletHitArmourEvent{Armour: component.Get.Armour(armour)})
+				if bullet.Piercing {
+					bullet.Last = armour
+					ent.Set(bullet)
+				} else {
+					_ = world.Remove(ent)
+				}
+				continue
+			}
+			enemy := cs.checkEnemies(ent, world, bullet.Last)
+			if enemy != nil {
+				world.Signal(BulletHitEnemyEvent{Enemy: enemy})
+				if bullet.Piercing {
+					bullet.Last = enemy
+					ent.Set(bullet)
+				} else {
+					_ = world.Remove(ent)
+				}
 				continue
 			}
 		} else if ent.Contains(component.TYPE.Plane) {
-			if cs.checkPlaneBlock(ent, world) {
-				cs.tintEntity(ent, world)
+			// a shielded plane pass through the blocks, and the enemies
+			if ent.NotContains(component.TYPE.Shield) {
+				hitBlock := cs.checkPlaneBlock(ent, world)
+				hitEnemy := cs.checkPlaneEnemy(ent, world)
+				hitHazard := cs.checkPlaneHazard(ent, world)
+				if hitBlock || hitEnemy || hitHazard {
+					cs.tintEntity(ent, world)
+				}
 			}
+			cs.checkPlanePickup(ent, world)
 		} else if ent.Contains(component.TYPE.Mesh) {
 			if cs.checkMeshBlock(ent, world) {
 				cs.tintEntity(ent, world)
@@ -73,16 +131,53 @@ func (cs *collisionSystem) blocksCollisionsSystem(world *goecs.World, _ float32)
 	return nil
 }
 
-func (cs *collisionSystem) checkBlocks(bullet *goecs.Entity, world *goecs.World) *goecs.Entity {
+func (cs *collisionSystem) checkBlocks(bullet *goecs.Entity, world *goecs.World, skip *goecs.Entity) *goecs.Entity {
 	for it := world.Iterator(component.TYPE.Block, geometry.TYPE.Point, sprite.TYPE); it != nil; it = it.Next() {
 		block := it.Value()
-		if cs.spriteCollide(bullet, block) {
+		if block != skip && cs.spriteCollide(bullet, block) {
 			return block
 		}
 	}
 	return nil
 }
 
+func (cs *collisionSystem) checkEnemies(bullet *goecs.Entity, world *goecs.World, skip *goecs.Entity) *goecs.Entity {
+	for it := world.Iterator(component.TYPE.Enemy, geometry.TYPE.Point, sprite.TYPE); it != nil; it = it.Next() {
+		enemy := it.Value()
+		if enemy != skip && cs.spriteCollide(bullet, enemy) {
+			return enemy
+		}
+	}
+	return nil
+}
+
+func (cs *collisionSystem) checkArmour(bullet *goecs.Entity, world *goecs.World, skip *goecs.Entity) *goecs.Entity {
+	for it := world.Iterator(component.TYPE.Armour, geometry.TYPE.Point, sprite.TYPE); it != nil; it = it.Next() {
+		armour := it.Value()
+		if armour != skip && cs.spriteCollide(bullet, armour) {
+			return armour
+		}
+	}
+	return nil
+}
+
+// check if a bullet is inside a solid hazard, and return it
+func (cs *collisionSystem) checkSolidHazard(bullet *goecs.Entity, world *goecs.World) *goecs.Entity {
+	pos := geometry.Get.Point(bullet)
+	for it := world.Iterator(component.TYPE.Hazard, geometry.TYPE.Point); it != nil; it = it.Next() {
+		ent := it.Value()
+		hazard := component.Get.Hazard(ent)
+		if !hazard.Solid {
+			continue
+		}
+		rect := geometry.Rect{From: geometry.Get.Point(ent), Size: hazard.Size}
+		if rect.IsPointInRect(pos) {
+			return ent
+		}
+	}
+	return nil
+}
+
 func (cs *collisionSystem) spriteCollide(ent1, ent2 *goecs.Entity) bool {
 	spr1 := sprite.Get(ent1)
 	pos1 := geometry.Get.Point(ent1)
@@ -99,25 +194,104 @@ func (cs *collisionSystem) checkPlaneBlock(plane *goecs.Entity, world *goecs.Wor
 		block := it.Value()
 		if cs.spriteCollide(plane, block) {
 			any = true
-			cs.removeBlock(block, world, true)
+			world.Signal(PlaneHitBlockEvent{Block: cs.removeBlock(block, world)})
+		}
+	}
+	return any
+}
+
+// check if the plane hit any enemy or enemy bullet, removing them
+func (cs *collisionSystem) checkPlaneEnemy(plane *goecs.Entity, world *goecs.World) bool {
+	any := false
+	for it := world.Iterator(component.TYPE.EnemyBullet, geometry.TYPE.Point, sprite.TYPE); it != nil; it = it.Next() {
+		bullet := it.Value()
+		if cs.spriteCollide(plane, bullet) {
+			any = true
+			at := geometry.Get.Point(bullet)
+			_ = world.Remove(bullet)
+			world.Signal(PlaneHitEnemyEvent{At: at})
+		}
+	}
+	for it := world.Iterator(component.TYPE.Enemy, geometry.TYPE.Point, sprite.TYPE); it != nil; it = it.Next() {
+		enemy := it.Value()
+		if cs.spriteCollide(plane, enemy) {
+			any = true
+			at := geometry.Get.Point(enemy)
+			enemyC := component.Get.Enemy(enemy)
+			_ = world.Remove(enemy)
+			world.Signal(PlaneHitEnemyEvent{Enemy: &enemyC, At: at})
 		}
 	}
 	return any
 }
 
+// check if the plane hit any hazard that damage it, a hazard only damage the plane once
+func (cs *collisionSystem) checkPlaneHazard(plane *goecs.Entity, world *goecs.World) bool {
+	spr := sprite.Get(plane)
+	size, err := cs.eng.GetSpriteSize(spr.Sheet, spr.Name)
+	if err != nil {
+		return false
+	}
+	size = size.Scale(spr.Scale)
+	pos := geometry.Get.Point(plane)
+	planeRect := geometry.Rect{
+		From: geometry.Point{X: pos.X - size.Width*0.5, Y: pos.Y - size.Height*0.5},
+		Size: size,
+	}
+
+	any := false
+	for it := world.Iterator(component.TYPE.Hazard, geometry.TYPE.Point); it != nil; it = it.Next() {
+		ent := it.Value()
+		hazard := component.Get.Hazard(ent)
+		if !hazard.Damage || hazard.Hit {
+			continue
+		}
+		rect := geometry.Rect{From: geometry.Get.Point(ent), Size: hazard.Size}
+		if overlaps(planeRect, rect) {
+			any = true
+			hazard.Hit = true
+			ent.Set(hazard)
+			world.Signal(PlaneHitHazardEvent{At: pos})
+		}
+	}
+	return any
+}
+
+// check if two rects overlap
+func overlaps(a, b geometry.Rect) bool {
+	return a.From.X < b.From.X+b.Size.Width && b.From.X < a.From.X+a.Size.Width &&
+		a.From.Y < b.From.Y+b.Size.Height && b.From.Y < a.From.Y+a.Size.Height
+}
+
+func (cs *collisionSystem) checkPlanePickup(plane *goecs.Entity, world *goecs.World) {
+	for it := world.Iterator(component.TYPE.Pickup, geometry.TYPE.Point, sprite.TYPE); it != nil; it = it.Next() {
+		pickup := it.Value()
+		if cs.spriteCollide(plane, pickup) {
+			pickupC := component.Get.Pickup(pickup)
+			if pickupC.Text != nil {
+				_ = world.Remove(pickupC.Text)
+			}
+			_ = world.Remove(pickup)
+			world.Signal(PlanePickupEvent{Pickup: pickupC})
+		}
+	}
+}
+
 func (cs *collisionSystem) checkMeshBlock(mesh *goecs.Entity, world *goecs.World) bool {
 	any := false
+	service := component.Get.Mesh(mesh).Service
 	for it := world.Iterator(component.TYPE.Block, geometry.TYPE.Point, sprite.TYPE); it != nil; it = it.Next() {
 		block := it.Value()
 		if cs.spriteCollide(mesh, block) {
 			any = true
-			cs.removeBlock(block, world, false)
+			world.Signal(MeshHitBlockEvent{Block: cs.removeBlock(block, world), Service: service})
 		}
 	}
 	return any
 }
 
-func (cs *collisionSystem) removeBlock(block *goecs.Entity, world *goecs.World, isPlane bool) {
+// remove a block that has been hit, and returns it
+func (cs *collisionSystem) removeBlock(block *goecs.Entity, world *goecs.World) component.Block {
 	blockC := component.Get.Block(block)
 	if blockC.Text != nil {
 		_ = world.Remove(blockC.Text)
@@ -126,23 +300,24 @@ func (cs *collisionSystem) removeBlock(block *goecs.Entity, world *goecs.World,
 	}
 
 	_ = world.Remove(block)
-	if isPlane {
-		world.Signal(PlaneHitBlockEvent{Block: blockC})
-	} else {
-		world.Signal(MeshHitBlockEvent{Block: blockC})
-	}
+	return blockC
 }
 
 func (cs *collisionSystem) tintEntity(ent *goecs.Entity, world *goecs.World) {
 	if ent.NotContains(effects.TYPE.AlternateColor) {
+		// keep the entity color, so we could restore it
+		original := color.White
+		if ent.Contains(color.TYPE.Solid) {
+			original = color.Get.Solid(ent)
+		}
 		ent.Add(effects.AlternateColor{
-			From:  color.White,
+			From:  original,
 			To:    color.Red,
 			Time:  0.15,
 			Delay: 0,
 		})
 		world.Signal(events.DelaySignal{
-			Signal: RemoveTintEvent{ent: ent},
+			Signal: RemoveTintEvent{ent: ent, original: original},
 			Time:   1.5,
 		})
 	}
@@ -151,16 +326,17 @@ func (cs *collisionSystem) tintEntity(ent *goecs.Entity, world *goecs.World) {
 func (cs *collisionSystem) removeTintsListener(_ *goecs.World, signal interface{}, _ float32) error {
 	switch e := signal.(type) {
 	case RemoveTintEvent:
-		e.ent.Remove(effects.TYPE.AlternateColor)
-		e.ent.Remove(effects.TYPE.AlternateColorState)
-		e.ent.Set(color.White)
+		// the tint could be removed while paused
+		pause.RemoveBlink(e.ent)
+		e.ent.Set(e.original)
 	}
 	return nil
 }
 
 // BulletHitBlockEvent is trigger when a bullet hit a block
 type BulletHitBlockEvent struct {
-	Block component.Block
+	Block  component.Block
+	Effect int // Effect of the bullet
 }
 
 // BulletHitBlockEventType is the reflect.Type of BulletHitBlockEventType
@@ -174,17 +350,60 @@ type PlaneHitBlockEvent struct {
 // PlaneHitBlockEventType is the reflect.Type of PlaneHitBlockEvent
 var PlaneHitBlockEventType = reflect.TypeOf(PlaneHitBlockEvent{})
 
-// MeshHitBlockEvent is trigger when the mesh hit a block
+// MeshHitBlockEvent is trigger when a mesh in the convoy hit a block
 type MeshHitBlockEvent struct {
-	Block component.Block
+	Block   component.Block
+	Service int // Service is the mesh position in the convoy
 }
 
 // MeshHitBlockEventType is the reflect.Type of MeshHitBlockEvent
 var MeshHitBlockEventType = reflect.TypeOf(MeshHitBlockEvent{})
 
+// BulletHitEnemyEvent is trigger when a bullet hit an enemy
+type BulletHitEnemyEvent struct {
+	Enemy *goecs.Entity
+}
+
+// BulletHitEnemyEventType is the reflect.Type of BulletHitEnemyEvent
+var BulletHitEnemyEventType = reflect.TypeOf(BulletHitEnemyEvent{})
+
+// BulletHitArmourEvent is trigger when a bullet hit the boss armour
+type BulletHitArmourEvent struct {
+	Armour component.Armour
+}
+
+// BulletHitArmourEventType is the reflect.Type of BulletHitArmourEvent
+var BulletHitArmourEventType = reflect.TypeOf(BulletHitArmourEvent{})
+
+// PlaneHitEnemyEvent is trigger when the plane hit an enemy or an enemy bullet
+type PlaneHitEnemyEvent struct {
+	Enemy *component.Enemy // Enemy that has been hit, nil if it was a bullet
+	At    geometry.Point   // At is where the plane was hit
+}
+
+// PlaneHitEnemyEventType is the reflect.Type of PlaneHitEnemyEvent
+var PlaneHitEnemyEventType = reflect.TypeOf(PlaneHitEnemyEvent{})
+
+// PlaneHitHazardEvent is trigger when the plane hit a hazard
+type PlaneHitHazardEvent struct {
+	At geometry.Point // At is where the plane was hit
+}
+
+// PlaneHitHazardEventType is the reflect.Type of PlaneHitHazardEvent
+var PlaneHitHazardEventType = reflect.TypeOf(PlaneHitHazardEvent{})
+
+// PlanePickupEvent is trigger when the plane collect a pickup
+type PlanePickupEvent struct {
+	Pickup component.Pickup
+}
+
+// PlanePickupEventType is the reflect.Type of PlanePickupEvent
+var PlanePickupEventType = reflect.TypeOf(PlanePickupEvent{})
+
 // RemoveTintEvent is a event to remove a tint
 type RemoveTintEvent struct {
-	ent *goecs.Entity
+	ent      *goecs.Entity
+	original color.Solid // original color of the entity
 }
 
 // RemoveTintEventType is the reflect.Type of RemoveTintEvent
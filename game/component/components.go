@@ -24,11 +24,16 @@ package component
 
 import (
 	"github.com/juan-medina/goecs"
+	"github.com/juan-medina/gosge/components/geometry"
 	"reflect"
 )
 
 // Bullet is a component for our bullets
-type Bullet struct{}
+type Bullet struct {
+	Effect   int           // Effect when hitting a block
+	Piercing bool          // Piercing bullets continue after hitting a block
+	Last     *goecs.Entity // Last block hit by a piercing bullet
+}
 
 // Block is a component for a map blocks
 type Block struct {
@@ -44,11 +49,50 @@ type FloatText struct{}
 type Plane struct{}
 
 // Mesh is a component for the mesh
-type Mesh struct{}
+type Mesh struct {
+	Service int // Service is the position of this mesh in the convoy
+}
 
 // Production is a component for the production area
 type Production struct{}
 
+// Pickup is a component for a power-up pickup
+type Pickup struct {
+	Kind int
+	Text *goecs.Entity
+}
+
+// Shield is a component for entities that are shielded from blocks
+type Shield struct{}
+
+// Enemy is a component for a rogue container
+type Enemy struct {
+	Kind     int     // Kind is the enemy kind in the catalogue
+	Health   int     // Health is the hits left to destroy it
+	Time     float32 // Time since it spawn
+	BaseY    float32 // BaseY is the Y that the enemy patterns move around
+	Cooldown float32 // Cooldown is the time left to fire again
+}
+
+// EnemyBullet is a component for the bullets fired by the enemies
+type EnemyBullet struct{}
+
+// Armour is a component for a cell in the boss armour
+type Armour struct {
+	C, R int
+	Weak bool // Weak is an exposed weak point
+}
+
+// Hazard is a component for an environmental hazard
+type Hazard struct {
+	Kind   int           // Kind of hazard
+	Size   geometry.Size // Size of the hazard area, from its position
+	Damage bool          // Damage indicates that the hazard damage the plane
+	Hit    bool          // Hit indicates that the hazard has already damage the plane
+	Solid  bool          // Solid indicates that the bullets ricochet off the hazard
+	Time   float32       // Time in the current state
+}
+
 type types struct {
 	// Bullet is the reflect.Type for component.Bullet
 	Bullet reflect.Type
@@ -62,16 +106,34 @@ type types struct {
 	Mesh reflect.Type
 	// Production is the reflect.Type for component.Production
 	Production reflect.Type
+	// Pickup is the reflect.Type for component.Pickup
+	Pickup reflect.Type
+	// Shield is the reflect.Type for component.Shield
+	Shield reflect.Type
+	// Enemy is the reflect.Type for component.Enemy
+	Enemy reflect.Type
+	// EnemyBullet is the reflect.Type for component.EnemyBullet
+	EnemyBullet reflect.Type
+	// Armour is the reflect.Type for component.Armour
+	Armour reflect.Type
+	// Hazard is the reflect.Type for component.Hazard
+	Hazard reflect.Type
 }
 
 // TYPE hold the reflect.Type for our components
 var TYPE = types{
-	Bullet:     reflect.TypeOf(Bullet{}),
-	Block:      reflect.TypeOf(Block{}),
-	FloatText:  reflect.TypeOf(FloatText{}),
-	Plane:      reflect.TypeOf(Plane{}),
-	Mesh:       reflect.TypeOf(Mesh{}),
-	Production: reflect.TypeOf(Production{}),
+	Bullet:      reflect.TypeOf(Bullet{}),
+	Block:       reflect.TypeOf(Block{}),
+	FloatText:   reflect.TypeOf(FloatText{}),
+	Plane:       reflect.TypeOf(Plane{}),
+	Mesh:        reflect.TypeOf(Mesh{}),
+	Production:  reflect.TypeOf(Production{}),
+	Pickup:      reflect.TypeOf(Pickup{}),
+	Shield:      reflect.TypeOf(Shield{}),
+	Enemy:       reflect.TypeOf(Enemy{}),
+	EnemyBullet: reflect.TypeOf(EnemyBullet{}),
+	Armour:      reflect.TypeOf(Armour{}),
+	Hazard:      reflect.TypeOf(Hazard{}),
 }
 
 type gets struct {
@@ -87,6 +149,16 @@ type gets struct {
 	Mesh func(e *goecs.Entity) Mesh
 	// Production gets a component.Production from a goecs.Entity
 	Production func(e *goecs.Entity) Production
+	// Pickup gets a component.Pickup from a goecs.Entity
+	Pickup func(e *goecs.Entity) Pickup
+	// Shield gets a component.Shield from a goecs.Entity
+	Shield func(e *goecs.Entity) Shield
+	// Enemy gets a component.Enemy from a goecs.Entity
+	Enemy func(e *goecs.Entity) Enemy
+	// Armour gets a component.Armour from a goecs.Entity
+	Armour func(e *goecs.Entity) Armour
+	// Hazard gets a component.Hazard from a goecs.Entity
+	Hazard func(e *goecs.Entity) Hazard
 }
 
 // Get a geometry component
@@ -116,4 +188,24 @@ var Get = gets{
 	Production: func(e *goecs.Entity) Production {
 		return e.Get(TYPE.Production).(Production)
 	},
+	// Pickup gets a component.Pickup from a goecs.Entity
+	Pickup: func(e *goecs.Entity) Pickup {
+		return e.Get(TYPE.Pickup).(Pickup)
+	},
+	// Shield gets a component.Shield from a goecs.Entity
+	Shield: func(e *goecs.Entity) Shield {
+		return e.Get(TYPE.Shield).(Shield)
+	},
+	// Enemy gets a component.Enemy from a goecs.Entity
+	Enemy: func(e *goecs.Entity) Enemy {
+		return e.Get(TYPE.Enemy).(Enemy)
+	},
+	// Armour gets a component.Armour from a goecs.Entity
+	Armour: func(e *goecs.Entity) Armour {
+		return e.Get(TYPE.Armour).(Armour)
+	},
+	// Hazard gets a component.Hazard from a goecs.Entity
+	Hazard: func(e *goecs.Entity) Hazard {
+		return e.Get(TYPE.Hazard).(Hazard)
+	},
 }
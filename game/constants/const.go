@@ -24,10 +24,29 @@ package constants
 
 // game constants
 const (
-	SpriteSheet         = "resources/sprites/mesh2prod.json" // game sprite sheet
-	CloudSizeConfig     = "cloud_size"                       // cloud side config value
-	MasterVolumeConfig  = "master_volume"                    // master volume config setting
-	DefaultMasterVolume = 1                                  // Default master volume
+	SpriteSheet            = "resources/sprites/mesh2prod.json" // game sprite sheet
+	CloudSizeConfig        = "cloud_size"                       // cloud side config value
+	MasterVolumeConfig     = "master_volume"                    // master volume config setting
+	DefaultMasterVolume    = 1                                  // Default master volume
+	BestGradeConfig        = "best_grade_%s"                    // best SLO grade config setting for each level
+	CampaignLevelConfig    = "campaign_level"                   // campaign level config setting
+	CampaignProgressConfig = "campaign_progress_%s"             // campaign progress config setting for each cloud
+	InputKeyConfig         = "input_key_%s"                     // key binding config setting for each action
+	InputButtonConfig      = "input_button_%s"                  // gamepad button binding config setting for each action
+	InputDeadZoneConfig    = "input_dead_zone"                  // gamepad stick dead zone config setting
+	DefaultDeadZone        = 0.2                                // Default gamepad stick dead zone
+	InputSchemeConfig      = "input_scheme"                     // control scheme config setting
+	InputTargetingConfig   = "input_targeting"                  // targeting mode config setting
+	WalletConfig           = "wallet"                           // BlockCoins wallet config setting
+	UpgradeConfig          = "upgrade_%s"                       // level config setting for each upgrade
+	SkinsFile              = "resources/skins/skins.json"       // skins catalogue
+	EnemiesFile            = "resources/enemies/enemies.json"   // enemies catalogue
+	LevelFile              = "resources/levels/%s.json"         // level data file for each environment
+	WavesFile              = "resources/waves/%s.waves"         // enemy waves file for each environment
+	ThemesFile             = "resources/themes/themes.json"     // themes catalogue
+	PlaneSkinConfig        = "plane_skin"                       // plane skin config setting
+	PayloadSkinConfig      = "payload_skin"                     // payload skin config setting
+	WeatherConfig          = "weather"                          // weather state config setting, forced with the -weather flag
 )
 
 // CloudSize is the cloud size
@@ -61,4 +80,12 @@ var (
 		CorpCloud:    300,
 		PublicCloud:  450,
 	}
+
+	// CloudTimeLimits is the time, in seconds, to reach production on each cloud size
+	CloudTimeLimits = map[CloudSize]float32{
+		LocalCloud:   150,
+		StartupCloud: 300,
+		CorpCloud:    540,
+		PublicCloud:  760,
+	}
 )
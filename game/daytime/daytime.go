@@ -0,0 +1,152 @@
+/*
+ * Copyright (c) 2020 Juan Medina.
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package daytime
+
+import (
+	"github.com/juan-medina/gosge/components/color"
+	"github.com/juan-medina/mesh2prod/game/constants"
+	"github.com/juan-medina/mesh2prod/game/theme"
+	"math"
+)
+
+// logic constants
+const (
+	glowLight = 0.3 // how much the blocks are lighten when they fully glow
+)
+
+// Look is how the sky, the clouds and the blocks look at a time of day
+type Look struct {
+	Tint   color.Solid // Tint that is blended into the sky
+	Amount float32     // Amount of Tint in the sky, from 0 to 1
+	Clouds float32     // Clouds alpha factor
+	Blocks color.Solid // Blocks tint
+	Glow   float32     // Glow of the blocks, from 0 to 1
+}
+
+// phase is a Look at a time of day
+type phase struct {
+	at   float32 // at time of day
+	look Look    // look of the phase
+}
+
+var (
+	// phases of the day, in time order
+	phases = []phase{
+		{at: 0, look: Look{
+			Tint:   color.Solid{R: 255, G: 150, B: 100, A: 255},
+			Amount: 0.45,
+			Clouds: 0.8,
+			Blocks: color.Solid{R: 255, G: 220, B: 200, A: 255},
+		}}, // dawn
+		{at: 0.3, look: Look{
+			Tint:   color.White,
+			Amount: 0,
+			Clouds: 1,
+			Blocks: color.White,
+		}}, // day
+		{at: 0.7, look: Look{
+			Tint:   color.Solid{R: 250, G: 110, B: 80, A: 255},
+			Amount: 0.5,
+			Clouds: 0.7,
+			Blocks: color.Solid{R: 230, G: 190, B: 180, A: 255},
+		}}, // dusk
+		{at: 0.9, look: Look{
+			Tint:   color.Solid{R: 15, G: 20, B: 50, A: 255},
+			Amount: 0.85,
+			Clouds: 0.3,
+			Blocks: color.Solid{R: 110, G: 110, B: 160, A: 255},
+			Glow:   1,
+		}}, // night
+	}
+)
+
+// Time returns the time of day for a level progress, from 0 at dawn to 1 at night,
+// longer levels run more of the day so the longest ones go from dawn to night
+func Time(progress float32, length int) float32 {
+	longest := float32(constants.CloudSizes[constants.PublicCloud])
+	time := progress * float32(length) / longest
+	if time > 1 {
+		return 1
+	}
+	return time
+}
+
+// At returns the Look for a time of day, blending the phases around it
+func At(time float32) Look {
+	if time <= phases[0].at {
+		return phases[0].look
+	}
+	for i := 1; i < len(phases); i++ {
+		to := phases[i]
+		if time < to.at {
+			from := phases[i-1]
+			return from.look.blend(to.look, (time-from.at)/(to.at-from.at))
+		}
+	}
+	return phases[len(phases)-1].look
+}
+
+// blend two Looks using an scale
+func (l Look) blend(other Look, scale float32) Look {
+	return Look{
+		Tint:   l.Tint.Blend(other.Tint, scale),
+		Amount: l.Amount + (other.Amount-l.Amount)*scale,
+		Clouds: l.Clouds + (other.Clouds-l.Clouds)*scale,
+		Blocks: l.Blocks.Blend(other.Blocks, scale),
+		Glow:   l.Glow + (other.Glow-l.Glow)*scale,
+	}
+}
+
+// Sky returns a theme.Sky with the Look
+func (l Look) Sky(sky theme.Sky) theme.Sky {
+	return theme.Sky{
+		From: sky.From.Blend(l.Tint.Alpha(sky.From.A), l.Amount),
+		To:   sky.To.Blend(l.Tint.Alpha(sky.To.A), l.Amount),
+	}
+}
+
+// Cloud returns a cloud color with the Look
+func (l Look) Cloud(clr color.Solid) color.Solid {
+	return clr.Alpha(uint8(float32(clr.A) * l.Clouds))
+}
+
+// Block returns a block color with the Look, blocks that glow keep their color and get lighter
+func (l Look) Block(clr color.Solid) color.Solid {
+	tinted := color.Solid{
+		R: uint8(uint16(clr.R) * uint16(l.Blocks.R) / 255),
+		G: uint8(uint16(clr.G) * uint16(l.Blocks.G) / 255),
+		B: uint8(uint16(clr.B) * uint16(l.Blocks.B) / 255),
+		A: clr.A,
+	}
+	glow := clr.Blend(color.White.Alpha(clr.A), glowLight)
+	return tinted.Blend(glow, l.Glow)
+}
+
+// Cycle returns the time of day for a time in a looping cycle, that go from dawn to night and back
+func Cycle(time, length float32) float32 {
+	t := float32(math.Mod(float64(time), float64(length))) / length * 2
+	if t > 1 {
+		return 2 - t
+	}
+	return t
+}
@@ -0,0 +1,161 @@
+/*
+ * Copyright (c) 2020 Juan Medina.
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package daytime
+
+import (
+	"fmt"
+	"github.com/juan-medina/gosge/components/color"
+	"github.com/juan-medina/mesh2prod/game/constants"
+	"github.com/juan-medina/mesh2prod/game/theme"
+	"testing"
+)
+
+func TestTime(t *testing.T) {
+	type tc struct {
+		progress float32
+		length   int
+		expect   float32
+	}
+
+	public := constants.CloudSizes[constants.PublicCloud]
+
+	cases := []tc{
+		{progress: 0, length: public, expect: 0},
+		{progress: 0.5, length: public, expect: 0.5},
+		{progress: 1, length: public, expect: 1},
+		{progress: 1, length: public / 2, expect: 0.5},
+		{progress: 0.5, length: public / 5, expect: 0.1},
+		{progress: 1, length: public * 2, expect: 1},
+	}
+
+	for i, v := range cases {
+		t.Run(fmt.Sprintf("case %d", i+1), func(t *testing.T) {
+			if got := Time(v.progress, v.length); got != v.expect {
+				t.Fatalf("time error, got %v, expect %v", got, v.expect)
+			}
+		})
+	}
+}
+
+func TestAt(t *testing.T) {
+	type tc struct {
+		time   float32
+		expect Look
+	}
+
+	cases := []tc{
+		{time: -1, expect: phases[0].look},
+		{time: 0, expect: phases[0].look},
+		{time: 0.3, expect: phases[1].look},
+		{time: 0.5, expect: phases[1].look.blend(phases[2].look, 0.5)},
+		{time: 0.7, expect: phases[2].look},
+		{time: 0.9, expect: phases[3].look},
+		{time: 1, expect: phases[3].look},
+	}
+
+	for i, v := range cases {
+		t.Run(fmt.Sprintf("case %d", i+1), func(t *testing.T) {
+			if got := At(v.time); got != v.expect {
+				t.Fatalf("look error, got %v, expect %v", got, v.expect)
+			}
+		})
+	}
+}
+
+func TestLook_Sky(t *testing.T) {
+	sky := theme.Sky{From: color.White, To: color.SkyBlue}
+
+	if got := At(0.3).Sky(sky); got != sky {
+		t.Fatalf("day sky error, got %v, expect %v", got, sky)
+	}
+
+	night := At(1)
+	got := night.Sky(sky)
+	if got.From.R >= sky.From.R || got.To.B >= sky.To.B {
+		t.Fatalf("night sky error, got %v, expect darker than %v", got, sky)
+	}
+	if got.From.A != sky.From.A || got.To.A != sky.To.A {
+		t.Fatalf("night sky alpha error, got %v, expect %v", got, sky)
+	}
+}
+
+func TestLook_Cloud(t *testing.T) {
+	type tc struct {
+		time   float32
+		expect uint8
+	}
+
+	cases := []tc{
+		{time: 0, expect: 160},
+		{time: 0.3, expect: 200},
+		{time: 1, expect: 60},
+	}
+
+	for i, v := range cases {
+		t.Run(fmt.Sprintf("case %d", i+1), func(t *testing.T) {
+			if got := At(v.time).Cloud(color.White.Alpha(200)).A; got != v.expect {
+				t.Fatalf("cloud alpha error, got %v, expect %v", got, v.expect)
+			}
+		})
+	}
+}
+
+func TestLook_Block(t *testing.T) {
+	clr := color.Solid{R: 200, G: 100, B: 50, A: 255}
+
+	if got := At(0.3).Block(clr); got != clr {
+		t.Fatalf("day block error, got %v, expect %v", got, clr)
+	}
+
+	if got := At(0.7).Block(clr); got.R >= clr.R || got.G >= clr.G || got.B >= clr.B {
+		t.Fatalf("dusk block error, got %v, expect darker than %v", got, clr)
+	}
+
+	if got := At(1).Block(clr); got.R <= clr.R || got.G <= clr.G || got.B <= clr.B {
+		t.Fatalf("night block error, got %v, expect lighter than %v", got, clr)
+	}
+}
+
+func TestCycle(t *testing.T) {
+	type tc struct {
+		time   float32
+		expect float32
+	}
+
+	cases := []tc{
+		{time: 0, expect: 0},
+		{time: 15, expect: 0.5},
+		{time: 30, expect: 1},
+		{time: 45, expect: 0.5},
+		{time: 60, expect: 0},
+		{time: 75, expect: 0.5},
+	}
+
+	for i, v := range cases {
+		t.Run(fmt.Sprintf("case %d", i+1), func(t *testing.T) {
+			if got := Cycle(v.time, 60); got != v.expect {
+				t.Fatalf("cycle error, got %v, expect %v", got, v.expect)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+/*
+ * Copyright (c) 2020 Juan Medina.
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package daytime
+
+import (
+	"github.com/juan-medina/goecs"
+	"github.com/juan-medina/gosge"
+	"github.com/juan-medina/mesh2prod/game/winning"
+	"reflect"
+)
+
+// logic constants
+const (
+	steps = 100 // steps in a day, we change the Look once per step
+)
+
+// ChangeEvent is trigger when the Look of the time of day changes
+type ChangeEvent struct {
+	Look Look // Look at the current time of day
+}
+
+// ChangeEventType is the reflect.Type of ChangeEvent
+var ChangeEventType = reflect.TypeOf(ChangeEvent{})
+
+type daytimeSystem struct {
+	length int // length of the level
+	step   int // current step of the day
+}
+
+// load the system
+func (ds *daytimeSystem) load(eng *gosge.Engine) error {
+	world := eng.World()
+
+	// listen to the progress
+	world.AddListener(ds.progressListener, winning.ProgressEventType)
+
+	// start at dawn
+	world.Signal(ChangeEvent{Look: At(0)})
+
+	return nil
+}
+
+// move the time of day with the level progress
+func (ds *daytimeSystem) progressListener(world *goecs.World, signal interface{}, _ float32) error {
+	switch e := signal.(type) {
+	case winning.ProgressEvent:
+		time := Time(e.Percent, ds.length)
+		if step := int(time * steps); step != ds.step {
+			ds.step = step
+			world.Signal(ChangeEvent{Look: At(time)})
+		}
+	}
+	return nil
+}
+
+// System create the day time system
+func System(engine *gosge.Engine, length int) error {
+	ds := daytimeSystem{
+		length: length,
+	}
+
+	return ds.load(engine)
+}
@@ -0,0 +1,166 @@
+/*
+ * Copyright (c) 2020 Juan Medina.
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package enemy
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/juan-medina/gosge/components/geometry"
+	"github.com/juan-medina/mesh2prod/game/skin"
+	"io/ioutil"
+	"math"
+)
+
+// Pattern is how an enemy fly
+type Pattern string
+
+// patterns
+const (
+	Sine  = Pattern("sine")  // Sine fly in a sine wave
+	Dive  = Pattern("dive")  // Dive fly toward the plane
+	Hover = Pattern("hover") // Hover stops on screen to shoot, and later leave
+)
+
+// Kind is a kind of enemy
+type Kind struct {
+	skin.Skin
+	Scale       float32 `json:"scale"`       // Scale for the sprite
+	Health      int     `json:"health"`      // Health is the hits needed to destroy it
+	Speed       float32 `json:"speed"`       // Speed flying to the left
+	Pattern     Pattern `json:"pattern"`     // Pattern that it fly
+	Amplitude   float32 `json:"amplitude"`   // Amplitude of the wave, for sine and hover
+	Frequency   float32 `json:"frequency"`   // Frequency of the wave in waves per second, for sine and hover
+	DiveSpeed   float32 `json:"diveSpeed"`   // DiveSpeed is the vertical speed toward the plane, for dive
+	HoverX      float32 `json:"hoverX"`      // HoverX is the screen width factor where it stops, for hover
+	HoverTime   float32 `json:"hoverTime"`   // HoverTime is the seconds since it spawn before leaving, for hover
+	FireDelay   float32 `json:"fireDelay"`   // FireDelay is the seconds between shots, 0 if it does not fire
+	BulletSpeed float32 `json:"bulletSpeed"` // BulletSpeed is the speed of its bullets
+	Coins       int     `json:"coins"`       // Coins is the BlockCoins that drops, as blocks cleared
+	Pickup      float32 `json:"pickup"`      // Pickup is the chance to drop a power-up pickup
+}
+
+// Catalogue is the available enemies
+type Catalogue struct {
+	Delay   float32 `json:"delay"`   // Delay is the average seconds between enemies
+	Enemies []Kind  `json:"enemies"` // Enemies kinds
+}
+
+// Load the Catalogue from a file
+func Load(fileName string) (Catalogue, error) {
+	var c Catalogue
+
+	data, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return c, err
+	}
+
+	if err = json.Unmarshal(data, &c); err != nil {
+		return c, err
+	}
+
+	if len(c.Enemies) == 0 {
+		return c, errors.New("enemies catalogue need at least an enemy")
+	}
+
+	for _, k := range c.Enemies {
+		switch k.Pattern {
+		case Sine, Dive, Hover:
+		default:
+			return c, fmt.Errorf("enemy %q has an unknown pattern %q", k.Name, k.Pattern)
+		}
+	}
+
+	return c, nil
+}
+
+// Sheets returns the sprite sheets used by the enemies, without repeating them
+func (c Catalogue) Sheets() []string {
+	sheets := make([]string, 0)
+	found := make(map[string]bool)
+	for _, k := range c.Enemies {
+		if !found[k.Sheet] {
+			found[k.Sheet] = true
+			sheets = append(sheets, k.Sheet)
+		}
+	}
+	return sheets
+}
+
+// Find the index of an enemy Kind by its name, and if it exist
+func (c Catalogue) Find(name string) (int, bool) {
+	for i, k := range c.Enemies {
+		if k.Name == name {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// Move returns where an enemy is after a delta time following its Pattern, time is the time since it spawn,
+// baseY is the Y that it waves around and width is the screen width
+func (k Kind) Move(pos geometry.Point, baseY, time, delta float32, plane geometry.Point, width, scale float32) geometry.Point {
+	speed := k.Speed * delta * scale
+	wave := baseY + k.Amplitude*scale*float32(math.Sin(2*math.Pi*float64(k.Frequency*time)))
+
+	switch k.Pattern {
+	case Sine:
+		pos.X -= speed
+		pos.Y = wave
+	case Dive:
+		pos.X -= speed
+		dive := k.DiveSpeed * delta * scale
+		if diff := plane.Y - pos.Y; diff > dive {
+			pos.Y += dive
+		} else if diff < -dive {
+			pos.Y -= dive
+		} else {
+			pos.Y = plane.Y
+		}
+	case Hover:
+		stop := width * k.HoverX
+		if time >= k.HoverTime {
+			pos.X -= speed
+		} else if pos.X -= speed; pos.X < stop {
+			pos.X = stop
+		}
+		pos.Y = wave
+	}
+
+	return pos
+}
+
+// Fires returns if this Kind of enemy fire bullets
+func (k Kind) Fires() bool {
+	return k.FireDelay > 0
+}
+
+// Aim returns the velocity for a bullet from a point to a target at a given speed
+func Aim(from, to geometry.Point, speed float32) geometry.Point {
+	diff := geometry.Point{X: to.X - from.X, Y: to.Y - from.Y}
+	length := float32(math.Sqrt(float64(diff.X*diff.X + diff.Y*diff.Y)))
+	if length == 0 {
+		return geometry.Point{X: -speed}
+	}
+	return geometry.Point{X: diff.X / length * speed, Y: diff.Y / length * speed}
+}
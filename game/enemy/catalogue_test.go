@@ -0,0 +1,130 @@
+/*
+ * Copyright (c) 2020 Juan Medina.
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package enemy
+
+import (
+	"fmt"
+	"github.com/juan-medina/gosge/components/geometry"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	c, err := Load("../../resources/enemies/enemies.json")
+	if err != nil {
+		t.Fatalf("load error, got %v, expect nil", err)
+	}
+
+	if len(c.Enemies) == 0 {
+		t.Fatalf("enemies error, got %v, expect > 0", len(c.Enemies))
+	}
+
+	if _, err = Load("not_found.json"); err == nil {
+		t.Fatalf("load error, got nil, expect error")
+	}
+}
+
+func TestKind_Move(t *testing.T) {
+	type tc struct {
+		kind   Kind
+		pos    geometry.Point
+		time   float32
+		expect geometry.Point
+	}
+
+	plane := geometry.Point{X: 0, Y: 100}
+
+	cases := []tc{
+		// sine at the top of the wave
+		{
+			kind:   Kind{Pattern: Sine, Speed: 100, Amplitude: 50, Frequency: 0.25},
+			pos:    geometry.Point{X: 500, Y: 0},
+			time:   1,
+			expect: geometry.Point{X: 400, Y: 250},
+		},
+		// dive toward the plane, limited by the dive speed
+		{
+			kind:   Kind{Pattern: Dive, Speed: 100, DiveSpeed: 50},
+			pos:    geometry.Point{X: 500, Y: 200},
+			time:   1,
+			expect: geometry.Point{X: 400, Y: 150},
+		},
+		// dive reach the plane
+		{
+			kind:   Kind{Pattern: Dive, Speed: 100, DiveSpeed: 50},
+			pos:    geometry.Point{X: 500, Y: 120},
+			time:   1,
+			expect: geometry.Point{X: 400, Y: 100},
+		},
+		// hover stops at its X
+		{
+			kind:   Kind{Pattern: Hover, Speed: 100, HoverX: 0.5, HoverTime: 5},
+			pos:    geometry.Point{X: 550, Y: 200},
+			time:   1,
+			expect: geometry.Point{X: 500, Y: 200},
+		},
+		// hover leaves after its time
+		{
+			kind:   Kind{Pattern: Hover, Speed: 100, HoverX: 0.5, HoverTime: 5},
+			pos:    geometry.Point{X: 500, Y: 200},
+			time:   6,
+			expect: geometry.Point{X: 400, Y: 200},
+		},
+	}
+
+	for i, v := range cases {
+		t.Run(fmt.Sprintf("case %d", i+1), func(t *testing.T) {
+			got := v.kind.Move(v.pos, 200, v.time, 1, plane, 1000, 1)
+			if !near(got.X, v.expect.X) || !near(got.Y, v.expect.Y) {
+				t.Fatalf("move error, got %v, expect %v", got, v.expect)
+			}
+		})
+	}
+}
+
+func TestAim(t *testing.T) {
+	type tc struct {
+		from   geometry.Point
+		to     geometry.Point
+		expect geometry.Point
+	}
+
+	cases := []tc{
+		{from: geometry.Point{X: 100, Y: 0}, to: geometry.Point{X: 0, Y: 0}, expect: geometry.Point{X: -10}},
+		{from: geometry.Point{X: 0, Y: 0}, to: geometry.Point{X: 30, Y: 40}, expect: geometry.Point{X: 6, Y: 8}},
+		{from: geometry.Point{X: 5, Y: 5}, to: geometry.Point{X: 5, Y: 5}, expect: geometry.Point{X: -10}},
+	}
+
+	for i, v := range cases {
+		t.Run(fmt.Sprintf("case %d", i+1), func(t *testing.T) {
+			got := Aim(v.from, v.to, 10)
+			if !near(got.X, v.expect.X) || !near(got.Y, v.expect.Y) {
+				t.Fatalf("aim error, got %v, expect %v", got, v.expect)
+			}
+		})
+	}
+}
+
+func near(a, b float32) bool {
+	d := a - b
+	return d > -0.001 && d < 0.001
+}
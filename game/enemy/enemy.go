@@ -0,0 +1,382 @@
+/*
+ * Copyright (c) 2020 Juan Medina.
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package enemy
+
+import (
+	"github.com/juan-medina/goecs"
+	"github.com/juan-medina/gosge"
+	"github.com/juan-medina/gosge/components/animation"
+	"github.com/juan-medina/gosge/components/color"
+	"github.com/juan-medina/gosge/components/effects"
+	"github.com/juan-medina/gosge/components/geometry"
+	"github.com/juan-medina/gosge/events"
+	"github.com/juan-medina/mesh2prod/game/collision"
+	"github.com/juan-medina/mesh2prod/game/component"
+	"github.com/juan-medina/mesh2prod/game/constants"
+	"github.com/juan-medina/mesh2prod/game/movement"
+	"github.com/juan-medina/mesh2prod/game/pause"
+	"github.com/juan-medina/mesh2prod/game/plane"
+	"github.com/juan-medina/mesh2prod/game/powerup"
+	"github.com/juan-medina/mesh2prod/game/score"
+	"github.com/juan-medina/mesh2prod/game/timeline"
+	"github.com/juan-medina/mesh2prod/game/winning"
+	"math/rand"
+	"reflect"
+)
+
+// logic constants
+const (
+	bulletSprite      = "bullet_%d.png"            // bullet sprite base
+	bulletScale       = 0.2                        // scale for the bullet sprite
+	bulletFrames      = 5                          // bullet frames
+	bulletFramesDelay = 0.065                      // bullet frame delay
+	bulletLife        = 5                          // bullet life in seconds
+	spawnGap          = 100                        // gap to the right of the screen where enemies spawn
+	minSpawnY         = 0.15                       // min screen height factor where enemies spawn
+	maxSpawnY         = 0.85                       // max screen height factor where enemies spawn
+	shotSound         = "resources/audio/shot.wav" // enemy shot sound
+	hitSound          = "resources/audio/hit.wav"  // enemy hit sound
+	popSound          = "resources/audio/pop.wav"  // enemy destroyed sound
+)
+
+var (
+	bulletColor = color.Solid{R: 255, G: 0, B: 255, A: 255} // enemy bullets color
+)
+
+// FireEvent is a signal to fire an enemy bullet to the plane
+type FireEvent struct {
+	From  geometry.Point // From is where the bullet is fired
+	Speed float32        // Speed of the bullet
+}
+
+// FireEventType is the reflect.Type of FireEvent
+var FireEventType = reflect.TypeOf(FireEvent{})
+
+// SpawnEvent is a signal to spawn an enemy
+type SpawnEvent struct {
+	Kind Kind           // Kind of enemy to spawn
+	At   geometry.Point // At is where the enemy spawn
+}
+
+// SpawnEventType is the reflect.Type of SpawnEvent
+var SpawnEventType = reflect.TypeOf(SpawnEvent{})
+
+type enemySystem struct {
+	pause.State
+	gs        geometry.Scale
+	dr        geometry.Size
+	catalogue Catalogue
+	kinds     []Kind         // kinds that could be spawn, the catalogue ones and their variations
+	index     map[Kind]int   // index of each kind in kinds
+	planePos  geometry.Point // current plane position
+	spawnIn   float32        // time to the next spawn
+	end       bool
+	boss      bool // is the boss stage on
+}
+
+// load the system
+func (es *enemySystem) load(eng *gosge.Engine) error {
+	var err error
+
+	// load the enemies sprite sheets, if they are not our sprite sheet
+	for _, sheet := range es.catalogue.Sheets() {
+		if sheet != constants.SpriteSheet {
+			if err = eng.LoadSpriteSheet(sheet); err != nil {
+				return err
+			}
+		}
+	}
+
+	// pre-load sounds
+	for _, sound := range []string{shotSound, hitSound, popSound} {
+		if err = eng.LoadSound(sound); err != nil {
+			return err
+		}
+	}
+
+	world := eng.World()
+
+	// spawn enemies
+	world.AddSystem(es.spawnSystem)
+
+	// listen to fire and spawn requests
+	world.AddListener(es.fireListener, FireEventType, SpawnEventType)
+
+	// listen to the timeline
+	world.AddListener(es.timelineListener, timeline.MarkEventType)
+
+	// move the enemies and fire
+	world.AddSystem(es.enemySystem)
+
+	// listen to the plane
+	world.AddListener(es.planeChanges, plane.PositionChangeEventType)
+
+	// listen to collisions
+	world.AddListener(es.collisionListener, collision.BulletHitEnemyEventType, collision.PlaneHitEnemyEventType)
+
+	// keep track of the pause
+	es.Track(world)
+
+	// listen to level events
+	world.AddListener(es.levelEvents, winning.LevelEndEventType,
+		winning.BossStageEventType, winning.BossDefeatedEventType)
+
+	es.spawnIn = es.nextSpawn()
+
+	return nil
+}
+
+// a random time for the next spawn, around the catalogue delay
+func (es enemySystem) nextSpawn() float32 {
+	return es.catalogue.Delay * (0.5 + rand.Float32())
+}
+
+// spawn enemies from time to time
+func (es *enemySystem) spawnSystem(world *goecs.World, delta float32) error {
+	// no enemies spawn during the boss stage
+	if es.end || es.IsPaused() || es.boss {
+		return nil
+	}
+	if es.spawnIn -= delta; es.spawnIn <= 0 {
+		es.spawnIn = es.nextSpawn()
+		es.spawn(world, rand.Intn(len(es.catalogue.Enemies)), 0)
+	}
+	return nil
+}
+
+// spawn an enemy off the screen on the right, shifted by a number of gaps
+func (es *enemySystem) spawn(world *goecs.World, kind int, shift int) {
+	height := es.dr.Height * es.gs.Max
+	pos := geometry.Point{
+		X: (es.dr.Width + spawnGap*float32(shift+1)) * es.gs.Max,
+		Y: height * (minSpawnY + rand.Float32()*(maxSpawnY-minSpawnY)),
+	}
+	es.add(world, es.catalogue.Enemies[kind], pos)
+}
+
+// add an enemy of a Kind in a position
+func (es *enemySystem) add(world *goecs.World, k Kind, pos geometry.Point) {
+	kind, ok := es.index[k]
+	if !ok {
+		kind = len(es.kinds)
+		es.kinds = append(es.kinds, k)
+		es.index[k] = kind
+	}
+	world.AddEntity(
+		animation.Animation{
+			Sequences: map[string]animation.Sequence{
+				"flying": {
+					Sheet:  k.Sheet,
+					Base:   k.Base,
+					Scale:  es.gs.Max * k.Scale,
+					Frames: k.Frames,
+					Delay:  k.Delay,
+				},
+			},
+			Current: "flying",
+			Speed:   1,
+		},
+		pos,
+		k.Color(),
+		component.Enemy{
+			Kind:     kind,
+			Health:   k.Health,
+			BaseY:    pos.Y,
+			Cooldown: k.FireDelay,
+		},
+		effects.Layer{Depth: 0},
+	)
+}
+
+// move the enemies following their pattern, fire to the plane and remove the ones that are gone
+func (es *enemySystem) enemySystem(world *goecs.World, delta float32) error {
+	if es.IsPaused() {
+		return nil
+	}
+
+	width := es.dr.Width * es.gs.Max
+
+	for it := world.Iterator(component.TYPE.Enemy, geometry.TYPE.Point); it != nil; it = it.Next() {
+		ent := it.Value()
+		enemy := component.Get.Enemy(ent)
+		k := es.kinds[enemy.Kind]
+
+		enemy.Time += delta
+		pos := k.Move(geometry.Get.Point(ent), enemy.BaseY, enemy.Time, delta, es.planePos, width, es.gs.Max)
+
+		// gone off the left of the screen
+		if pos.X < -spawnGap*es.gs.Max {
+			_ = world.Remove(ent)
+			continue
+		}
+
+		// fire when is on screen and in front of the plane
+		if k.Fires() && !es.end && pos.X < width && pos.X > es.planePos.X {
+			if enemy.Cooldown -= delta; enemy.Cooldown <= 0 {
+				enemy.Cooldown = k.FireDelay
+				es.fire(world, pos, k.BulletSpeed)
+			}
+		}
+
+		ent.Set(pos)
+		ent.Set(enemy)
+	}
+
+	return nil
+}
+
+// fire a bullet to the plane
+func (es *enemySystem) fire(world *goecs.World, from geometry.Point, speed float32) {
+	world.AddEntity(
+		animation.Animation{
+			Sequences: map[string]animation.Sequence{
+				"moving": {
+					Sheet:  constants.SpriteSheet,
+					Base:   bulletSprite,
+					Scale:  es.gs.Max * bulletScale,
+					Frames: bulletFrames,
+					Delay:  bulletFramesDelay,
+				},
+			},
+			Current: "moving",
+			Speed:   1,
+		},
+		from,
+		movement.Projectile{
+			Velocity: Aim(from, es.planePos, speed),
+			Life:     bulletLife,
+		},
+		bulletColor,
+		component.EnemyBullet{},
+		effects.Layer{Depth: 0},
+		movement.Drift{},
+	)
+	world.Signal(events.PlaySoundEvent{Name: shotSound, Volume: 0.5})
+}
+
+// fire a bullet, or spawn an enemy, when is requested
+func (es *enemySystem) fireListener(world *goecs.World, signal interface{}, _ float32) error {
+	if es.end {
+		return nil
+	}
+	switch e := signal.(type) {
+	case FireEvent:
+		es.fire(world, e.From, e.Speed)
+	case SpawnEvent:
+		es.add(world, e.Kind, e.At)
+	}
+	return nil
+}
+
+// spawn the enemies scripted in the timeline
+func (es *enemySystem) timelineListener(world *goecs.World, signal interface{}, _ float32) error {
+	if es.end {
+		return nil
+	}
+	switch e := signal.(type) {
+	case timeline.MarkEvent:
+		if e.Mark.Event != timeline.SpawnEvent {
+			return nil
+		}
+		if kind, ok := es.catalogue.Find(e.Mark.Value); ok {
+			for i := 0; i < e.Mark.Count; i++ {
+				es.spawn(world, kind, i)
+			}
+		}
+	}
+	return nil
+}
+
+// damage the enemies that are hit, and reward the ones destroyed
+func (es *enemySystem) collisionListener(world *goecs.World, signal interface{}, _ float32) error {
+	if es.end {
+		return nil
+	}
+	switch e := signal.(type) {
+	case collision.BulletHitEnemyEvent:
+		enemy := component.Get.Enemy(e.Enemy)
+		// it may been destroyed already by other bullet
+		if enemy.Health <= 0 {
+			return nil
+		}
+		if enemy.Health--; enemy.Health > 0 {
+			e.Enemy.Set(enemy)
+			world.Signal(events.PlaySoundEvent{Name: hitSound, Volume: 1})
+			return nil
+		}
+		e.Enemy.Set(enemy)
+		es.destroy(world, e.Enemy, es.kinds[enemy.Kind])
+	case collision.PlaneHitEnemyEvent:
+		// crashing or getting shot cost like hitting a block
+		world.Signal(score.PointsEvent{Total: -1, At: e.At})
+		world.Signal(events.PlaySoundEvent{Name: hitSound, Volume: 1})
+	}
+	return nil
+}
+
+// destroy an enemy dropping its BlockCoins, and maybe a pickup
+func (es *enemySystem) destroy(world *goecs.World, ent *goecs.Entity, k Kind) {
+	at := geometry.Get.Point(ent)
+	_ = world.Remove(ent)
+	if k.Coins > 0 {
+		world.Signal(score.PointsEvent{Total: k.Coins, At: at})
+	}
+	if rand.Float32() < k.Pickup {
+		world.Signal(powerup.DropEvent{At: at})
+	}
+	world.Signal(events.PlaySoundEvent{Name: popSound, Volume: 1})
+}
+
+// keep track of the plane position
+func (es *enemySystem) planeChanges(_ *goecs.World, signal interface{}, _ float32) error {
+	switch e := signal.(type) {
+	case plane.PositionChangeEvent:
+		es.planePos = e.Pos
+	}
+	return nil
+}
+
+func (es *enemySystem) levelEvents(_ *goecs.World, signal interface{}, _ float32) error {
+	switch signal.(type) {
+	case winning.LevelEndEvent:
+		es.end = true
+	case winning.BossStageEvent:
+		es.boss = true
+	case winning.BossDefeatedEvent:
+		es.boss = false
+	}
+	return nil
+}
+
+// System create the enemy system
+func System(engine *gosge.Engine, gs geometry.Scale, dr geometry.Size, catalogue Catalogue) error {
+	es := enemySystem{
+		gs:        gs,
+		dr:        dr,
+		catalogue: catalogue,
+		kinds:     make([]Kind, 0),
+		index:     make(map[Kind]int),
+	}
+
+	return es.load(engine)
+}
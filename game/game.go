@@ -27,15 +27,28 @@ import (
 	"github.com/juan-medina/gosge/components/geometry"
 	"github.com/juan-medina/gosge/events"
 	"github.com/juan-medina/mesh2prod/game/background"
+	"github.com/juan-medina/mesh2prod/game/boss"
 	"github.com/juan-medina/mesh2prod/game/collision"
 	"github.com/juan-medina/mesh2prod/game/constants"
+	"github.com/juan-medina/mesh2prod/game/daytime"
+	"github.com/juan-medina/mesh2prod/game/enemy"
 	"github.com/juan-medina/mesh2prod/game/gamemap"
+	"github.com/juan-medina/mesh2prod/game/hazard"
+	"github.com/juan-medina/mesh2prod/game/input"
+	"github.com/juan-medina/mesh2prod/game/level"
 	"github.com/juan-medina/mesh2prod/game/mesh"
 	"github.com/juan-medina/mesh2prod/game/movement"
 	"github.com/juan-medina/mesh2prod/game/music"
+	"github.com/juan-medina/mesh2prod/game/pause"
 	"github.com/juan-medina/mesh2prod/game/plane"
+	"github.com/juan-medina/mesh2prod/game/powerup"
 	"github.com/juan-medina/mesh2prod/game/score"
+	"github.com/juan-medina/mesh2prod/game/skin"
 	"github.com/juan-medina/mesh2prod/game/target"
+	"github.com/juan-medina/mesh2prod/game/theme"
+	"github.com/juan-medina/mesh2prod/game/timeline"
+	"github.com/juan-medina/mesh2prod/game/wave"
+	"github.com/juan-medina/mesh2prod/game/weather"
 	"github.com/juan-medina/mesh2prod/game/winning"
 	"math/rand"
 	"time"
@@ -82,31 +95,100 @@ func Stage(eng *gosge.Engine) error {
 		return err
 	}
 
+	// load the skins
+	var skins skin.Catalogue
+	if skins, err = skin.Load(constants.SkinsFile); err != nil {
+		return err
+	}
+
+	// load the skins sprite sheets, if they are not our sprite sheet
+	for _, sheet := range skins.Sheets() {
+		if sheet != constants.SpriteSheet {
+			if err = eng.LoadSpriteSheet(sheet); err != nil {
+				return err
+			}
+		}
+	}
+
+	// load the enemies
+	var enemies enemy.Catalogue
+	if enemies, err = enemy.Load(constants.EnemiesFile); err != nil {
+		return err
+	}
+
+	// load the themes
+	var themes theme.Catalogue
+	if themes, err = theme.Load(constants.ThemesFile); err != nil {
+		return err
+	}
+
+	// get the current campaign level, and its theme
+	lvl := level.Current(eng.GetSettings())
+	lvlTheme := themes.For(lvl)
+
+	// load the theme sprite sheet, if is not our sprite sheet
+	if lvlTheme.Sheet != constants.SpriteSheet {
+		if err = eng.LoadSpriteSheet(lvlTheme.Sheet); err != nil {
+			return err
+		}
+	}
+
+	// get the selected skins
+	planeSkin, payloadSkin := skins.Selected(eng.GetSettings())
+
+	// add the input system
+	if err = input.System(eng); err != nil {
+		return err
+	}
+
 	// add movement system
 	if err = movement.System(eng, gameScale); err != nil {
 		return err
 	}
 
 	// add the plane
-	if err = plane.System(eng, gameScale, designResolution); err != nil {
+	if err = plane.System(eng, gameScale, designResolution, planeSkin); err != nil {
 		return err
 	}
 
 	// add the background system
-	if err = background.System(eng, gameScale, designResolution); err != nil {
+	if err = background.System(eng, gameScale, designResolution, lvlTheme); err != nil {
+		return err
+	}
+
+	// add the day time system
+	if err = daytime.System(eng, lvl.Length); err != nil {
+		return err
+	}
+
+	// add the mesh convoy
+	if err = mesh.System(eng, gameScale, designResolution, payloadSkin, lvl.Services); err != nil {
 		return err
 	}
 
-	// add the mesh
-	if err = mesh.System(eng, gameScale, designResolution); err != nil {
+	// load the level timeline
+	var tl timeline.Timeline
+	if tl, err = timeline.Load(lvl.File(), map[string]timeline.Check{
+		timeline.SpawnEvent: func(value string) bool {
+			_, ok := enemies.Find(value)
+			return ok
+		},
+		timeline.WeatherEvent: func(value string) bool {
+			_, ok := weather.Parse(value)
+			return ok
+		},
+	}); err != nil {
 		return err
 	}
 
-	cs := constants.CloudSize(eng.GetSettings().GetIn32(constants.CloudSizeConfig, int32(constants.StartupCloud)))
-	length := constants.CloudSizes[cs]
+	// load the level waves
+	var waves []wave.Wave
+	if waves, err = wave.Load(lvl.WavesFile(), enemies); err != nil {
+		return err
+	}
 
 	// add the map
-	if err = gamemap.System(eng, gameScale, designResolution, int(length)); err != nil {
+	if err = gamemap.System(eng, gameScale, designResolution, lvl.Length, lvl.Density, lvlTheme.Palette); err != nil {
 		return err
 	}
 
@@ -115,6 +197,41 @@ func Stage(eng *gosge.Engine) error {
 		return err
 	}
 
+	// add the power-up system
+	if err = powerup.System(eng, gameScale, designResolution); err != nil {
+		return err
+	}
+
+	// add the enemy system
+	if err = enemy.System(eng, gameScale, designResolution, enemies); err != nil {
+		return err
+	}
+
+	// add the boss system
+	if err = boss.System(eng, gameScale, designResolution); err != nil {
+		return err
+	}
+
+	// add the timeline system
+	if err = timeline.System(eng, gameScale, designResolution, tl); err != nil {
+		return err
+	}
+
+	// add the wave system
+	if err = wave.System(eng, gameScale, designResolution, waves); err != nil {
+		return err
+	}
+
+	// add the hazard system
+	if err = hazard.System(eng, gameScale, designResolution); err != nil {
+		return err
+	}
+
+	// add the weather system
+	if err = weather.System(eng, gameScale, designResolution); err != nil {
+		return err
+	}
+
 	// add the collision system
 	if err = collision.System(eng); err != nil {
 		return err
@@ -126,7 +243,12 @@ func Stage(eng *gosge.Engine) error {
 	}
 
 	// add the winning system
-	if err = winning.System(eng, gameScale, designResolution); err != nil {
+	if err = winning.System(eng, gameScale, designResolution, lvl); err != nil {
+		return err
+	}
+
+	// add the pause system, after any system that listen to pause
+	if err = pause.System(eng, gameScale, designResolution); err != nil {
 		return err
 	}
 
@@ -37,9 +37,18 @@ import (
 	"github.com/juan-medina/mesh2prod/game/collision"
 	"github.com/juan-medina/mesh2prod/game/component"
 	"github.com/juan-medina/mesh2prod/game/constants"
+	"github.com/juan-medina/mesh2prod/game/daytime"
+	"github.com/juan-medina/mesh2prod/game/grid"
+	"github.com/juan-medina/mesh2prod/game/input"
 	"github.com/juan-medina/mesh2prod/game/movement"
+	"github.com/juan-medina/mesh2prod/game/pause"
+	"github.com/juan-medina/mesh2prod/game/plane"
+	"github.com/juan-medina/mesh2prod/game/powerup"
 	"github.com/juan-medina/mesh2prod/game/score"
+	"github.com/juan-medina/mesh2prod/game/weapon"
+	"math"
 	"math/rand"
+	"reflect"
 	"strconv"
 	"strings"
 )
@@ -65,9 +74,11 @@ const (
 	fontSize           = 30                               // top text fon size
 	fontProduction     = "resources/fonts/go_regular.fnt" // our production text font
 	fontProductionSize = 60                               // top production text fon size
+	smartTargetGap     = 100                              // smart target gap from gun pos
 )
 
 type gameMapSystem struct {
+	pause.State
 	rows         int               // number of rows
 	cols         int               // number of cols
 	data         [][]blocState     // map block state
@@ -78,10 +89,16 @@ type gameMapSystem struct {
 	scrollMarker *goecs.Entity     // track the scroll position
 	eng          *gosge.Engine     // the game engine
 	length       int               // our map length
+	density      int               // additional pieces per each group of pieces
+	gunPos       geometry.Point    // plane gun position
+	smartTarget  *goecs.Entity     // current smart lock target
+	palette      []color.Solid     // blocks colors
+	look         daytime.Look      // look of the blocks at the current time of day
 }
 
 var (
-	colors = []color.Solid{
+	// defaultPalette is the blocks colors, when the theme does not have any
+	defaultPalette = []color.Solid{
 		color.Yellow,
 		color.Gold,
 		color.Orange,
@@ -118,53 +135,24 @@ func (gms *gameMapSystem) set(c, r int, state blocState) {
 
 // place a block and mark the block that need to be clear
 func (gms *gameMapSystem) place(c, r int) {
+	areas := gms.findAreas(c, r)
+
 	// we set this block to place
 	gms.data[c][r] = placed
 
-	// search the top row
-	var tr int
-	for tr = r; tr >= 0; tr-- {
-		if gms.data[c][tr] == empty {
-			tr++
-			break
-		}
-	}
-
-	// search the right column
-	var sc int
-	for sc = c; sc < gms.cols; sc++ {
-		if gms.data[sc][r] == empty {
-			sc--
-			break
-		}
+	for _, a := range areas {
+		gms.clearArea(a.FromC, a.FromR, a.ToC, a.ToR)
 	}
+}
 
-	// search the bottom row
-	var br int
-	for br = r; br < gms.rows; br++ {
-		if gms.data[c][br] == empty {
-			br--
-			break
-		}
-	}
+// find the areas that we could clear placing a block, without placing it
+func (gms *gameMapSystem) findAreas(c, r int) []grid.Area {
+	return grid.FindAreas(gms.cols, gms.rows, gms.filled, c, r)
+}
 
-	// check for areas
-	for cc := c + 1; cc <= sc; cc++ {
-		// areas on top of the place block
-		for cr := r - 1; cr >= tr; cr-- {
-			can := gms.canClearArea(c, cr, cc, r)
-			if can {
-				gms.clearArea(c, cr, cc, r)
-			}
-		}
-		// areas under the place block
-		for cr := br; cr > r; cr-- {
-			can := gms.canClearArea(c, r, cc, cr)
-			if can {
-				gms.clearArea(c, r, cc, cr)
-			}
-		}
-	}
+// is there any block in a position
+func (gms *gameMapSystem) filled(c, r int) bool {
+	return gms.data[c][r] != empty
 }
 
 // add a block in a position
@@ -178,39 +166,6 @@ func (gms *gameMapSystem) add(col, row int, piece [][]blocState, color int) {
 	}
 }
 
-// can we clear an area? is a square area
-func (gms *gameMapSystem) canClearArea(fromC, fromR, toC, toR int) bool {
-	// top row
-	for c := fromC; c <= toC; c++ {
-		if gms.data[c][fromR] == empty {
-			return false
-		}
-	}
-
-	// bottom row
-	for c := fromC; c <= toC; c++ {
-		if gms.data[c][toR] == empty {
-			return false
-		}
-	}
-
-	// left column
-	for r := fromR; r <= toR; r++ {
-		if gms.data[fromC][r] == empty {
-			return false
-		}
-	}
-
-	// right column
-	for r := fromR; r <= toR; r++ {
-		if gms.data[toC][r] == empty {
-			return false
-		}
-	}
-
-	return true
-}
-
 // clear the area
 func (gms *gameMapSystem) clearArea(fromC, fromR, toC, toR int) {
 	for c := fromC; c <= toC; c++ {
@@ -246,6 +201,7 @@ func (gms *gameMapSystem) clearArea(fromC, fromR, toC, toR int) {
 								X: -blockSpeed * gms.gs.Max,
 							},
 						},
+						movement.Scroll{},
 						effects.Layer{Depth: -1},
 					)
 				}
@@ -255,6 +211,22 @@ func (gms *gameMapSystem) clearArea(fromC, fromR, toC, toR int) {
 	}
 }
 
+// tint the blocks with the time of day, blocks placed or been clear keep their color
+func (gms *gameMapSystem) daytimeListener(_ *goecs.World, signal interface{}, _ float32) error {
+	switch e := signal.(type) {
+	case daytime.ChangeEvent:
+		gms.look = e.Look
+		for c := 0; c < gms.cols; c++ {
+			for r := 0; r < gms.rows; r++ {
+				if gms.data[c][r] >= fill && gms.sprs[c][r] != nil {
+					gms.sprs[c][r].Set(gms.look.Block(gms.palette[(gms.data[c][r] - fill)]))
+				}
+			}
+		}
+	}
+	return nil
+}
+
 // create a new game map
 func newGameMap(cols, rows int) *gameMapSystem {
 	data := make([][]blocState, cols)
@@ -264,10 +236,12 @@ func newGameMap(cols, rows int) *gameMapSystem {
 		sprs[c] = make([]*goecs.Entity, rows)
 	}
 	return &gameMapSystem{
-		rows: rows,
-		cols: cols,
-		data: data,
-		sprs: sprs,
+		rows:    rows,
+		cols:    cols,
+		data:    data,
+		sprs:    sprs,
+		palette: defaultPalette,
+		look:    daytime.At(0),
 	}
 }
 
@@ -349,6 +323,18 @@ func (gms *gameMapSystem) load(eng *gosge.Engine) error {
 	// listen to collisions
 	world.AddListener(gms.collisionListener, collision.BulletHitBlockEventType, collision.PlaneHitBlockEventType, collision.MeshHitBlockEventType)
 
+	// keep track of the pause
+	gms.Track(world)
+
+	// listen to the time of day
+	world.AddListener(gms.daytimeListener, daytime.ChangeEventType)
+
+	// with smart lock we look for the block that clear the largest area
+	if input.Load(eng.GetSettings()).Targeting == input.SmartLock {
+		world.AddSystem(gms.smartTargetSystem)
+		world.AddListener(gms.planeChanges, plane.PositionChangeEventType)
+	}
+
 	return nil
 }
 
@@ -433,7 +419,7 @@ func (gms *gameMapSystem) generate() {
 
 	for cc < limitC {
 		// random number of pieces
-		num := 2 + rand.Intn(6)
+		num := 2 + rand.Intn(6) + gms.density
 		// fil the pieces
 		for i := 0; i < num; i++ {
 			// random shift of column
@@ -442,7 +428,7 @@ func (gms *gameMapSystem) generate() {
 			p := rand.Intn(len(pieces))
 			// random shift of row
 			r := 4 + rand.Intn(limitR)
-			clr := rand.Intn(len(colors))
+			clr := rand.Intn(len(gms.palette))
 			// add piece
 			gms.add(c, r, pieces[p], clr)
 		}
@@ -482,8 +468,8 @@ func (gms *gameMapSystem) addSprites(world *goecs.World) {
 				Scale: gms.gs.Max * blockScale,
 			})
 
-			clr := colors[(gms.data[c][r] - fill)]
-			ent.Add(clr)
+			clr := gms.palette[(gms.data[c][r] - fill)]
+			ent.Add(gms.look.Block(clr))
 			ent.Add(effects.Layer{Depth: 0})
 			ent.Add(component.Block{
 				C: c,
@@ -562,14 +548,19 @@ func (gms *gameMapSystem) addEntity(world *goecs.World, col, row int, offset flo
 				X: -blockSpeed * gms.gs.Max,
 			},
 		},
+		movement.Scroll{},
 	)
 }
 
 func (gms *gameMapSystem) bulletSystem(world *goecs.World, _ float32) error {
+	if gms.IsPaused() {
+		return nil
+	}
 	for it := world.Iterator(component.TYPE.Bullet, geometry.TYPE.Point); it != nil; it = it.Next() {
 		bullet := it.Value()
 		pos := geometry.Get.Point(bullet)
-		if pos.X >= gms.dr.Width*gms.gs.Max {
+		// remove the bullets off the screen sides, either flying ahead or back after a ricochet
+		if pos.X >= gms.dr.Width*gms.gs.Max || pos.X < 0 {
 			_ = world.Remove(bullet)
 		}
 	}
@@ -579,6 +570,14 @@ func (gms *gameMapSystem) bulletSystem(world *goecs.World, _ float32) error {
 func (gms *gameMapSystem) collisionListener(world *goecs.World, signal interface{}, _ float32) error {
 	switch e := signal.(type) {
 	case collision.BulletHitBlockEvent:
+		switch weapon.Effect(e.Effect) {
+		case weapon.DestroyBlock:
+			gms.destroyBlock(e.Block, world)
+			return nil
+		case weapon.PaintBlock:
+			gms.paintBlock(e.Block, world)
+			return nil
+		}
 		// get the current scroll
 		pos := geometry.Get.Point(gms.scrollMarker)
 		c := e.Block.C - 1
@@ -613,6 +612,33 @@ func (gms *gameMapSystem) collisionListener(world *goecs.World, signal interface
 	}
 	return nil
 }
+
+// remove a block, unless is already been clear
+func (gms *gameMapSystem) destroyBlock(block component.Block, world *goecs.World) {
+	if block.C >= 0 && block.C <= gms.cols && block.R >= 0 && block.R <= gms.rows {
+		spr := gms.sprs[block.C][block.R]
+		if spr != nil && gms.data[block.C][block.R] != clear {
+			_ = world.Remove(spr)
+			gms.data[block.C][block.R] = empty
+			gms.sprs[block.C][block.R] = nil
+			world.Signal(events.PlaySoundEvent{Name: popSound, Volume: 1})
+		}
+	}
+}
+
+// turn a block into a placed block, unless is already placed or been clear
+func (gms *gameMapSystem) paintBlock(block component.Block, world *goecs.World) {
+	if block.C >= 0 && block.C <= gms.cols && block.R >= 0 && block.R <= gms.rows {
+		spr := gms.sprs[block.C][block.R]
+		state := gms.data[block.C][block.R]
+		if spr != nil && state != clear && state != placed {
+			spr.Set(color.Red)
+			gms.place(block.C, block.R)
+			world.Signal(events.PlaySoundEvent{Name: hitSound, Volume: 1})
+		}
+	}
+}
+
 func (gms *gameMapSystem) clearBlock(block component.Block, world *goecs.World) {
 	if block.C >= 0 && block.C <= gms.cols && block.R >= 0 && block.R <= gms.rows {
 		spr := gms.sprs[block.C][block.R]
@@ -627,6 +653,11 @@ func (gms *gameMapSystem) clearBlock(block component.Block, world *goecs.World)
 }
 
 func (gms *gameMapSystem) clearSystem(world *goecs.World, delta float32) error {
+	// clear timers are frozen while paused
+	if gms.IsPaused() {
+		return nil
+	}
+
 	// total block we clear
 	total := 0
 
@@ -678,6 +709,9 @@ func (gms *gameMapSystem) clearSystem(world *goecs.World, delta float32) error {
 		// signal that we got points at a position
 		world.Signal(score.PointsEvent{Total: total, At: at})
 
+		// a power-up may spawn at the same position
+		world.Signal(powerup.SpawnEvent{At: at, Cleared: total})
+
 		// play pop sound
 		world.Signal(events.PlaySoundEvent{Name: popSound, Volume: 1})
 	}
@@ -685,14 +719,93 @@ func (gms *gameMapSystem) clearSystem(world *goecs.World, delta float32) error {
 	return nil
 }
 
+// keep track of the plane gun position
+func (gms *gameMapSystem) planeChanges(_ *goecs.World, signal interface{}, _ float32) error {
+	switch e := signal.(type) {
+	case plane.PositionChangeEvent:
+		gms.gunPos = e.Gun
+	}
+	return nil
+}
+
+// find the first block on each row that when we place a block in front of it will clear the largest area
+func (gms *gameMapSystem) smartTargetSystem(world *goecs.World, _ float32) error {
+	if gms.IsPaused() {
+		return nil
+	}
+
+	// visible columns after the gun
+	marker := geometry.Get.Point(gms.scrollMarker)
+	width := gms.blockSize.Width * gms.gs.Max * blockScale
+	fromC := int((gms.gunPos.X+smartTargetGap*gms.gs.Max-marker.X)/width) + 1
+	toC := int((gms.dr.Width*gms.gs.Max - marker.X) / width)
+	if fromC < 1 {
+		fromC = 1
+	}
+	if toC >= gms.cols {
+		toC = gms.cols - 1
+	}
+
+	var found *goecs.Entity = nil
+	largest := 0
+	closeY := float32(2000000)
+
+	for r := 0; r < gms.rows; r++ {
+		for c := fromC; c <= toC; c++ {
+			spr := gms.sprs[c][r]
+			if spr == nil {
+				continue
+			}
+			// only the first block in the row could be hit
+			if gms.data[c][r] != clear && gms.data[c-1][r] == empty {
+				size := 0
+				for _, a := range gms.findAreas(c-1, r) {
+					if a.Size() > size {
+						size = a.Size()
+					}
+				}
+				// on a draw, prefer the closest to the gun
+				diffY := float32(math.Abs(float64(geometry.Get.Point(spr).Y - gms.gunPos.Y)))
+				if size > largest || (size == largest && size > 0 && diffY < closeY) {
+					largest = size
+					closeY = diffY
+					found = spr
+				}
+			}
+			break
+		}
+	}
+
+	if found != gms.smartTarget {
+		gms.smartTarget = found
+		world.Signal(SmartTargetEvent{Block: found})
+	}
+
+	return nil
+}
+
 // System create the map system
-func System(engine *gosge.Engine, gs geometry.Scale, dr geometry.Size, length int) error {
+func System(engine *gosge.Engine, gs geometry.Scale, dr geometry.Size, length, density int,
+	palette []color.Solid) error {
 	gms := newGameMap(length+100, 34)
 
+	if len(palette) > 0 {
+		gms.palette = palette
+	}
+
 	gms.length = length
+	gms.density = density
 	gms.gs = gs
 	gms.dr = dr
 	gms.eng = engine
 
 	return gms.load(engine)
 }
+
+// SmartTargetEvent is trigger when the block that will clear the largest area change, Block is nil if there is none
+type SmartTargetEvent struct {
+	Block *goecs.Entity // Block to target
+}
+
+// SmartTargetEventType is the reflect.Type of SmartTargetEvent
+var SmartTargetEventType = reflect.TypeOf(SmartTargetEvent{})
@@ -381,6 +381,72 @@ func TestGameMap_Place(t *testing.T) {
 	}
 }
 
+func TestGameMap_FindAreas(t *testing.T) {
+	type tc struct {
+		given   string
+		placeR  int
+		placeC  int
+		largest int
+	}
+
+	cases := []tc{
+		{
+			given: "" +
+				"        " + "\n" +
+				"   3333 " + "\n" +
+				"    333 " + "\n" +
+				"   3333 " + "\n" +
+				"        " + "\n",
+			placeC:  3,
+			placeR:  2,
+			largest: 8,
+		},
+		{
+			given: "" +
+				"        " + "\n" +
+				"    333 " + "\n" +
+				"    3 3 " + "\n" +
+				"        " + "\n" +
+				"        " + "\n",
+			placeC:  3,
+			placeR:  1,
+			largest: 0,
+		},
+		{
+			given: "" +
+				"        " + "\n" +
+				"   33   " + "\n" +
+				"    3   " + "\n" +
+				"        " + "\n" +
+				"        " + "\n",
+			placeC:  3,
+			placeR:  2,
+			largest: 4,
+		},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("case %d", i+1), func(t *testing.T) {
+			gm := fromString(c.given)
+			got := 0
+			for _, a := range gm.findAreas(c.placeC, c.placeR) {
+				if a.Size() > got {
+					got = a.Size()
+				}
+			}
+
+			if got != c.largest {
+				t.Fatalf("find areas error, got %v, expect %v", got, c.largest)
+			}
+
+			// finding areas does not change the map
+			if str := gm.String(); str != c.given {
+				t.Fatalf("find areas change error, got %v, expect %v", str, c.given)
+			}
+		})
+	}
+}
+
 func TestGameMap_Add(t *testing.T) {
 	gm := newGameMap(10, 10)
 
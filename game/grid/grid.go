@@ -0,0 +1,95 @@
+/*
+ * Copyright (c) 2020 Juan Medina.
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package grid
+
+// Filled returns if a cell of a grid is filled
+type Filled func(c, r int) bool
+
+// Area is a rectangle of cells in a grid
+type Area struct {
+	FromC, FromR, ToC, ToR int
+}
+
+// Size of the Area in cells
+func (a Area) Size() int {
+	return (a.ToC - a.FromC + 1) * (a.ToR - a.FromR + 1)
+}
+
+// FindAreas returns the areas, with a cell in its left column, that are surrounded by filled cells,
+// the cell is taken as filled
+func FindAreas(cols, rows int, filled Filled, c, r int) []Area {
+	is := func(fc, fr int) bool {
+		return (fc == c && fr == r) || filled(fc, fr)
+	}
+
+	// search the top row
+	tr := r
+	for tr > 0 && is(c, tr-1) {
+		tr--
+	}
+
+	// search the right column
+	sc := c
+	for sc < cols-1 && is(sc+1, r) {
+		sc++
+	}
+
+	// search the bottom row
+	br := r
+	for br < rows-1 && is(c, br+1) {
+		br++
+	}
+
+	areas := make([]Area, 0)
+	for cc := c + 1; cc <= sc; cc++ {
+		// areas on top of the cell
+		for cr := r - 1; cr >= tr; cr-- {
+			if a := (Area{FromC: c, FromR: cr, ToC: cc, ToR: r}); Surrounded(is, a) {
+				areas = append(areas, a)
+			}
+		}
+		// areas under the cell
+		for cr := br; cr > r; cr-- {
+			if a := (Area{FromC: c, FromR: r, ToC: cc, ToR: cr}); Surrounded(is, a) {
+				areas = append(areas, a)
+			}
+		}
+	}
+
+	return areas
+}
+
+// Surrounded returns if the border of an Area is filled
+func Surrounded(filled Filled, a Area) bool {
+	for c := a.FromC; c <= a.ToC; c++ {
+		if !filled(c, a.FromR) || !filled(c, a.ToR) {
+			return false
+		}
+	}
+	for r := a.FromR; r <= a.ToR; r++ {
+		if !filled(a.FromC, r) || !filled(a.ToC, r) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,153 @@
+/*
+ * Copyright (c) 2020 Juan Medina.
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package grid
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// a grid from an string, where anything that is not a space is filled
+func fromString(str string) (int, int, Filled) {
+	lines := strings.Split(strings.TrimSuffix(str, "\n"), "\n")
+	filled := func(c, r int) bool {
+		return c < len(lines[r]) && lines[r][c] != ' '
+	}
+	return len(lines[0]), len(lines), filled
+}
+
+func TestFindAreas(t *testing.T) {
+	type tc struct {
+		given   string
+		c       int
+		r       int
+		expect  int
+		largest int
+	}
+
+	cases := []tc{
+		{
+			given: "" +
+				"        " + "\n" +
+				"   #### " + "\n" +
+				"    ### " + "\n" +
+				"   #### " + "\n" +
+				"        " + "\n",
+			c:       3,
+			r:       2,
+			expect:  6,
+			largest: 8,
+		},
+		{
+			given: "" +
+				"        " + "\n" +
+				"    ### " + "\n" +
+				"    # # " + "\n" +
+				"        " + "\n" +
+				"        " + "\n",
+			c:       3,
+			r:       1,
+			expect:  0,
+			largest: 0,
+		},
+		{
+			given: "" +
+				"        " + "\n" +
+				"   ##   " + "\n" +
+				"    #   " + "\n" +
+				"        " + "\n" +
+				"        " + "\n",
+			c:       3,
+			r:       2,
+			expect:  1,
+			largest: 4,
+		},
+		{
+			given: "" +
+				" ### " + "\n" +
+				"  ## " + "\n",
+			c:       1,
+			r:       1,
+			expect:  2,
+			largest: 6,
+		},
+		{
+			given: "" +
+				"##" + "\n" +
+				"##" + "\n",
+			c:       1,
+			r:       1,
+			expect:  0,
+			largest: 0,
+		},
+	}
+
+	for i, v := range cases {
+		t.Run(fmt.Sprintf("case %d", i+1), func(t *testing.T) {
+			cols, rows, filled := fromString(v.given)
+			areas := FindAreas(cols, rows, filled, v.c, v.r)
+			if len(areas) != v.expect {
+				t.Fatalf("find areas error, got %v, expect %v", len(areas), v.expect)
+			}
+			largest := 0
+			for _, a := range areas {
+				if a.Size() > largest {
+					largest = a.Size()
+				}
+			}
+			if largest != v.largest {
+				t.Fatalf("largest area error, got %v, expect %v", largest, v.largest)
+			}
+		})
+	}
+}
+
+func TestSurrounded(t *testing.T) {
+	_, _, filled := fromString("" +
+		"####" + "\n" +
+		"#  #" + "\n" +
+		"####" + "\n" +
+		"#  #" + "\n")
+
+	type tc struct {
+		area   Area
+		expect bool
+	}
+
+	cases := []tc{
+		{area: Area{FromC: 0, FromR: 0, ToC: 3, ToR: 2}, expect: true},
+		{area: Area{FromC: 0, FromR: 0, ToC: 3, ToR: 3}, expect: false},
+		{area: Area{FromC: 0, FromR: 0, ToC: 1, ToR: 0}, expect: true},
+		{area: Area{FromC: 0, FromR: 0, ToC: 2, ToR: 2}, expect: false},
+		{area: Area{FromC: 1, FromR: 1, ToC: 1, ToR: 1}, expect: false},
+	}
+
+	for i, v := range cases {
+		t.Run(fmt.Sprintf("case %d", i+1), func(t *testing.T) {
+			if got := Surrounded(filled, v.area); got != v.expect {
+				t.Fatalf("surrounded error, got %v, expect %v", got, v.expect)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+/*
+ * Copyright (c) 2020 Juan Medina.
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package hazard
+
+import "math"
+
+// Kind is a kind of hazard
+type Kind int
+
+// hazards
+const (
+	Firewall  = Kind(iota) // Firewall is a laser that sweep the screen, after a telegraph
+	Storm                  // Storm is a packet storm that push the plane
+	Gate                   // Gate is a rate-limiter gate that open and close
+	Lightning              // Lightning is a storm bolt that strike, after a telegraph
+)
+
+// timings
+const (
+	telegraphTime = 1.5 // seconds that a firewall is telegraphed before sweeping
+	gateOpen      = 2.5 // seconds that a gate is open
+	gateWarning   = 1   // seconds that a gate warns before closing
+	gateClosed    = 1.5 // seconds that a gate is closed
+	boltCharge    = 1   // seconds that a lightning is telegraphed before striking
+	boltStrike    = 0.3 // seconds that a lightning strike
+)
+
+// GateState is the state of a rate-limiter gate
+type GateState int
+
+// gate states
+const (
+	Open    = GateState(iota) // Open let the plane pass
+	Warning                   // Warning the gate is about to close, but the plane could pass
+	Closed                    // Closed damage the plane
+)
+
+// GateAt returns the GateState for a time since the gate spawn, gates cycle open, warning and closed
+func GateAt(time float32) GateState {
+	t := float32(math.Mod(float64(time), gateOpen+gateWarning+gateClosed))
+	switch {
+	case t < gateOpen:
+		return Open
+	case t < gateOpen+gateWarning:
+		return Warning
+	default:
+		return Closed
+	}
+}
+
+// Sweeping returns if a firewall is sweeping, for a time since it spawn, or is still telegraphed
+func Sweeping(time float32) bool {
+	return time >= telegraphTime
+}
+
+// BoltState is the state of a lightning
+type BoltState int
+
+// lightning states
+const (
+	Charging = BoltState(iota) // Charging the lightning is telegraphed
+	Striking                   // Striking damage the plane
+	Gone                       // Gone the lightning has strike
+)
+
+// BoltAt returns the BoltState for a time since the lightning spawn
+func BoltAt(time float32) BoltState {
+	switch {
+	case time < boltCharge:
+		return Charging
+	case time < boltCharge+boltStrike:
+		return Striking
+	default:
+		return Gone
+	}
+}
@@ -0,0 +1,100 @@
+/*
+ * Copyright (c) 2020 Juan Medina.
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package hazard
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestGateAt(t *testing.T) {
+	type tc struct {
+		time   float32
+		expect GateState
+	}
+
+	cases := []tc{
+		{time: 0, expect: Open},
+		{time: 2.4, expect: Open},
+		{time: 2.5, expect: Warning},
+		{time: 3.4, expect: Warning},
+		{time: 3.5, expect: Closed},
+		{time: 4.9, expect: Closed},
+		{time: 5, expect: Open},
+		{time: 8.6, expect: Closed},
+	}
+
+	for i, v := range cases {
+		t.Run(fmt.Sprintf("case %d", i+1), func(t *testing.T) {
+			if got := GateAt(v.time); got != v.expect {
+				t.Fatalf("gate error, got %v, expect %v", got, v.expect)
+			}
+		})
+	}
+}
+
+func TestSweeping(t *testing.T) {
+	type tc struct {
+		time   float32
+		expect bool
+	}
+
+	cases := []tc{
+		{time: 0, expect: false},
+		{time: 1.4, expect: false},
+		{time: 1.5, expect: true},
+		{time: 10, expect: true},
+	}
+
+	for i, v := range cases {
+		t.Run(fmt.Sprintf("case %d", i+1), func(t *testing.T) {
+			if got := Sweeping(v.time); got != v.expect {
+				t.Fatalf("sweeping error, got %v, expect %v", got, v.expect)
+			}
+		})
+	}
+}
+
+func TestBoltAt(t *testing.T) {
+	type tc struct {
+		time   float32
+		expect BoltState
+	}
+
+	cases := []tc{
+		{time: 0, expect: Charging},
+		{time: 0.9, expect: Charging},
+		{time: 1, expect: Striking},
+		{time: 1.2, expect: Striking},
+		{time: 1.3, expect: Gone},
+		{time: 5, expect: Gone},
+	}
+
+	for i, v := range cases {
+		t.Run(fmt.Sprintf("case %d", i+1), func(t *testing.T) {
+			if got := BoltAt(v.time); got != v.expect {
+				t.Fatalf("bolt error, got %v, expect %v", got, v.expect)
+			}
+		})
+	}
+}
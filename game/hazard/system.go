@@ -0,0 +1,401 @@
+/*
+ * Copyright (c) 2020 Juan Medina.
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package hazard
+
+import (
+	"github.com/juan-medina/goecs"
+	"github.com/juan-medina/gosge"
+	"github.com/juan-medina/gosge/components/color"
+	"github.com/juan-medina/gosge/components/effects"
+	"github.com/juan-medina/gosge/components/geometry"
+	"github.com/juan-medina/gosge/components/shapes"
+	"github.com/juan-medina/gosge/components/ui"
+	"github.com/juan-medina/gosge/events"
+	"github.com/juan-medina/mesh2prod/game/collision"
+	"github.com/juan-medina/mesh2prod/game/component"
+	"github.com/juan-medina/mesh2prod/game/movement"
+	"github.com/juan-medina/mesh2prod/game/pause"
+	"github.com/juan-medina/mesh2prod/game/score"
+	"github.com/juan-medina/mesh2prod/game/timeline"
+	"github.com/juan-medina/mesh2prod/game/winning"
+	"math/rand"
+	"reflect"
+)
+
+// logic constants
+const (
+	scrollSpeed  = 25                               // hazards scroll speed (match block scroll)
+	laserWidth   = 24                               // firewall laser width
+	sweepSpeed   = 450                              // firewall sweep speed
+	gateWidth    = 40                               // rate-limiter gate width
+	stormWidth   = 450                              // packet storm width
+	stormHeight  = 0.35                             // packet storm height, as screen height factor
+	stormPush    = 220                              // packet storm push speed
+	boltWidth    = 30                               // lightning bolt width
+	spawnGap     = 50                               // gap to the right of the screen where hazards spawn
+	font         = "resources/fonts/go_regular.fnt" // hazards text font
+	fontSize     = 40                               // hazards text font size
+	warningSound = "resources/audio/overheat.wav"   // hazard warning sound
+	hitSound     = "resources/audio/hit.wav"        // hazard hit sound
+)
+
+var (
+	telegraphColor = effects.AlternateColor{
+		From:  color.Red.Alpha(30),
+		To:    color.Red.Alpha(150),
+		Time:  0.15,
+		Delay: 0,
+	} // firewall telegraph blink
+	warningColor = effects.AlternateColor{
+		From:  color.Orange.Alpha(60),
+		To:    color.Orange,
+		Time:  0.15,
+		Delay: 0,
+	} // gate warning blink
+	chargeColor = effects.AlternateColor{
+		From:  color.Yellow.Alpha(20),
+		To:    color.Yellow.Alpha(120),
+		Time:  0.1,
+		Delay: 0,
+	} // lightning telegraph blink
+	gateOpenColor   = color.Gray.Alpha(60)   // gate open color
+	gateClosedColor = color.Orange           // gate closed color
+	stormColor      = color.Purple.Alpha(60) // packet storm color
+	laserColor      = color.Red              // firewall laser color
+	boltColor       = color.White            // lightning strike color
+	labelColor      = color.White.Alpha(180) // hazards text color
+)
+
+type hazardSystem struct {
+	pause.State
+	gs     geometry.Scale
+	dr     geometry.Size
+	labels map[*goecs.Entity]*goecs.Entity // text labels of the hazards
+	pushes map[*goecs.Entity]float32       // vertical push of the packet storms
+	end    bool
+}
+
+// load the system
+func (hs *hazardSystem) load(eng *gosge.Engine) error {
+	var err error
+
+	// pre-load font
+	if err = eng.LoadFont(font); err != nil {
+		return err
+	}
+
+	// pre-load sounds
+	for _, sound := range []string{warningSound, hitSound} {
+		if err = eng.LoadSound(sound); err != nil {
+			return err
+		}
+	}
+
+	world := eng.World()
+
+	// listen to the timeline
+	world.AddListener(hs.timelineListener, timeline.MarkEventType)
+
+	// listen to lightning strikes
+	world.AddListener(hs.lightningListener, LightningEventType)
+
+	// update the hazards
+	world.AddSystem(hs.hazardSystem)
+
+	// listen to hazard hits
+	world.AddListener(hs.collisionListener, collision.PlaneHitHazardEventType)
+
+	// keep track of the pause
+	hs.Track(world)
+
+	// listen to level events
+	world.AddListener(hs.levelEvents, winning.LevelEndEventType)
+
+	return nil
+}
+
+// add the hazards scripted in the timeline
+func (hs *hazardSystem) timelineListener(world *goecs.World, signal interface{}, _ float32) error {
+	if hs.end {
+		return nil
+	}
+	switch e := signal.(type) {
+	case timeline.MarkEvent:
+		switch e.Mark.Event {
+		case timeline.FirewallEvent:
+			hs.addFirewall(world)
+		case timeline.StormEvent:
+			push := float32(stormPush)
+			if e.Mark.Value == "up" {
+				push = -stormPush
+			}
+			hs.addStorm(world, push)
+		case timeline.GateEvent:
+			hs.addGate(world)
+		}
+	}
+	return nil
+}
+
+// add the lightning strikes from the weather
+func (hs *hazardSystem) lightningListener(world *goecs.World, signal interface{}, _ float32) error {
+	if hs.end {
+		return nil
+	}
+	switch e := signal.(type) {
+	case LightningEvent:
+		hs.addLightning(world, e.X)
+	}
+	return nil
+}
+
+// add a hazard area, in design units, with its color
+func (hs *hazardSystem) addHazard(world *goecs.World, kind Kind, pos geometry.Point, size geometry.Size,
+	clr color.Solid) *goecs.Entity {
+	return world.AddEntity(
+		shapes.SolidBox{
+			Size:  size,
+			Scale: hs.gs.Max,
+		},
+		geometry.Point{X: pos.X * hs.gs.Max, Y: pos.Y * hs.gs.Max},
+		clr,
+		component.Hazard{
+			Kind: int(kind),
+			Size: size.Scale(hs.gs.Max),
+		},
+		effects.Layer{Depth: 0.5},
+	)
+}
+
+// add a text label to a hazard, that move with it
+func (hs *hazardSystem) addLabel(world *goecs.World, hazard *goecs.Entity, text string, size geometry.Size) {
+	pos := geometry.Get.Point(hazard)
+	pos.X += size.Width * 0.5 * hs.gs.Max
+	pos.Y += size.Height * 0.5 * hs.gs.Max
+	hs.labels[hazard] = world.AddEntity(
+		ui.Text{
+			String:     text,
+			Size:       fontSize * hs.gs.Max,
+			Font:       font,
+			VAlignment: ui.MiddleVAlignment,
+			HAlignment: ui.CenterHAlignment,
+		},
+		pos,
+		labelColor,
+		hazard.Get(movement.Type),
+		movement.Scroll{},
+		effects.Layer{Depth: 0.4},
+	)
+}
+
+// scroll a hazard with the map
+func (hs hazardSystem) scroll(ent *goecs.Entity) {
+	ent.Add(movement.Movement{
+		Amount: geometry.Point{
+			X: -scrollSpeed * hs.gs.Max,
+		},
+	})
+	ent.Add(movement.Scroll{})
+}
+
+// add a firewall telegraph on the right of the screen, it will sweep the screen later
+func (hs *hazardSystem) addFirewall(world *goecs.World) {
+	size := geometry.Size{Width: laserWidth, Height: hs.dr.Height}
+	pos := geometry.Point{X: hs.dr.Width - laserWidth, Y: 0}
+	ent := hs.addHazard(world, Firewall, pos, size, telegraphColor.From)
+	ent.Add(telegraphColor)
+	world.Signal(events.PlaySoundEvent{Name: warningSound, Volume: 0.5})
+}
+
+// add a packet storm that scroll with the map
+func (hs *hazardSystem) addStorm(world *goecs.World, push float32) {
+	size := geometry.Size{Width: stormWidth, Height: hs.dr.Height * stormHeight}
+	pos := geometry.Point{
+		X: hs.dr.Width + spawnGap,
+		Y: rand.Float32() * (hs.dr.Height - size.Height),
+	}
+	ent := hs.addHazard(world, Storm, pos, size, stormColor)
+	hs.scroll(ent)
+	hs.pushes[ent] = push
+	text := "Packet Storm v"
+	if push < 0 {
+		text = "Packet Storm ^"
+	}
+	hs.addLabel(world, ent, text, size)
+}
+
+// add a rate-limiter gate that scroll with the map
+func (hs *hazardSystem) addGate(world *goecs.World) {
+	size := geometry.Size{Width: gateWidth, Height: hs.dr.Height}
+	pos := geometry.Point{X: hs.dr.Width + spawnGap, Y: 0}
+	ent := hs.addHazard(world, Gate, pos, size, gateOpenColor)
+	hs.scroll(ent)
+}
+
+// add a lightning telegraph at a X position, it will strike later
+func (hs *hazardSystem) addLightning(world *goecs.World, x float32) {
+	size := geometry.Size{Width: boltWidth, Height: hs.dr.Height}
+	pos := geometry.Point{X: x - boltWidth/2, Y: 0}
+	ent := hs.addHazard(world, Lightning, pos, size, chargeColor.From)
+	ent.Add(chargeColor)
+	world.Signal(events.PlaySoundEvent{Name: warningSound, Volume: 0.3})
+}
+
+// update the hazards states, and remove the ones that are gone
+func (hs *hazardSystem) hazardSystem(world *goecs.World, delta float32) error {
+	if hs.IsPaused() {
+		return nil
+	}
+
+	plane := world.Iterator(component.TYPE.Plane).Value()
+	planePos := geometry.Get.Point(plane)
+
+	for it := world.Iterator(component.TYPE.Hazard, geometry.TYPE.Point); it != nil; it = it.Next() {
+		ent := it.Value()
+		hazard := component.Get.Hazard(ent)
+		pos := geometry.Get.Point(ent)
+
+		// gone off the left of the screen
+		if pos.X+hazard.Size.Width < 0 {
+			hs.remove(world, ent)
+			continue
+		}
+
+		prev := hazard.Time
+		hazard.Time += delta
+
+		switch Kind(hazard.Kind) {
+		case Firewall:
+			// start sweeping after the telegraph
+			if !Sweeping(prev) && Sweeping(hazard.Time) {
+				hazard.Damage = true
+				hazard.Solid = true
+				hs.setColor(ent, laserColor)
+				ent.Add(movement.Movement{
+					Amount: geometry.Point{
+						X: -sweepSpeed * hs.gs.Max,
+					},
+				})
+			}
+		case Gate:
+			if state := GateAt(hazard.Time); state != GateAt(prev) {
+				switch state {
+				case Open:
+					hazard.Hit = false
+					hs.setColor(ent, gateOpenColor)
+				case Warning:
+					hs.setColor(ent, warningColor.From)
+					ent.Add(warningColor)
+				case Closed:
+					hs.setColor(ent, gateClosedColor)
+				}
+				hazard.Damage = state == Closed
+				hazard.Solid = state == Closed
+			}
+		case Lightning:
+			switch state := BoltAt(hazard.Time); state {
+			case Striking:
+				if state != BoltAt(prev) {
+					hazard.Damage = true
+					hs.setColor(ent, boltColor)
+				}
+			case Gone:
+				hs.remove(world, ent)
+				continue
+			}
+		case Storm:
+			// storms push the plane while is inside
+			rect := geometry.Rect{From: pos, Size: hazard.Size}
+			if !hs.end && rect.IsPointInRect(planePos) {
+				planePos.Y += hs.pushes[ent] * delta * hs.gs.Max
+				if plane.Contains(movement.ConstrainType) {
+					constrain := plane.Get(movement.ConstrainType).(movement.Constrain)
+					planePos.Clamp(constrain.Min, constrain.Max)
+				}
+				plane.Set(planePos)
+			}
+		}
+
+		ent.Set(hazard)
+	}
+
+	return nil
+}
+
+// set the color of a hazard, removing any blink
+func (hs hazardSystem) setColor(ent *goecs.Entity, clr color.Solid) {
+	ent.Remove(effects.TYPE.AlternateColor)
+	ent.Remove(effects.TYPE.AlternateColorState)
+	ent.Set(clr)
+}
+
+// remove a hazard and its label
+func (hs *hazardSystem) remove(world *goecs.World, ent *goecs.Entity) {
+	if label, ok := hs.labels[ent]; ok {
+		_ = world.Remove(label)
+		delete(hs.labels, ent)
+	}
+	delete(hs.pushes, ent)
+	_ = world.Remove(ent)
+}
+
+// hitting a hazard cost like hitting a block
+func (hs *hazardSystem) collisionListener(world *goecs.World, signal interface{}, _ float32) error {
+	if hs.end {
+		return nil
+	}
+	switch e := signal.(type) {
+	case collision.PlaneHitHazardEvent:
+		world.Signal(score.PointsEvent{Total: -1, At: e.At})
+		world.Signal(events.PlaySoundEvent{Name: hitSound, Volume: 1})
+	}
+	return nil
+}
+
+func (hs *hazardSystem) levelEvents(_ *goecs.World, signal interface{}, _ float32) error {
+	switch signal.(type) {
+	case winning.LevelEndEvent:
+		hs.end = true
+	}
+	return nil
+}
+
+// LightningEvent is a signal to strike a lightning
+type LightningEvent struct {
+	X float32 // X position of the lightning, in design units
+}
+
+// LightningEventType is the reflect.Type of LightningEvent
+var LightningEventType = reflect.TypeOf(LightningEvent{})
+
+// System create the hazard system
+func System(engine *gosge.Engine, gs geometry.Scale, dr geometry.Size) error {
+	hs := hazardSystem{
+		gs:     gs,
+		dr:     dr,
+		labels: make(map[*goecs.Entity]*goecs.Entity),
+		pushes: make(map[*goecs.Entity]float32),
+	}
+
+	return hs.load(engine)
+}
@@ -0,0 +1,194 @@
+/*
+ * Copyright (c) 2020 Juan Medina.
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package input
+
+import (
+	"github.com/juan-medina/goecs"
+	"github.com/juan-medina/gosge"
+	"github.com/juan-medina/gosge/components/device"
+	"github.com/juan-medina/gosge/components/geometry"
+	"github.com/juan-medina/gosge/events"
+	"reflect"
+)
+
+// ActionEvent is trigger when an Action is pressed or released
+type ActionEvent struct {
+	Action  Action // Action that has change
+	Pressed bool   // Pressed indicates if the action has been pressed or released
+}
+
+// ActionEventType is the reflect.Type of ActionEvent
+var ActionEventType = reflect.TypeOf(ActionEvent{})
+
+// AxisEvent is trigger when the left gamepad stick moves outside the dead zone, or back into it
+type AxisEvent struct {
+	Movement geometry.Point // Movement of the stick, from -1 to 1 in each axis
+}
+
+// AxisEventType is the reflect.Type of AxisEvent
+var AxisEventType = reflect.TypeOf(AxisEvent{})
+
+// AimEvent is trigger when the right gamepad stick moves vertically outside the dead zone, or back into it
+type AimEvent struct {
+	Amount float32 // Amount of vertical movement of the stick, from -1 to 1
+}
+
+// AimEventType is the reflect.Type of AimEvent
+var AimEventType = reflect.TypeOf(AimEvent{})
+
+// PointerEvent is trigger when the pointer moves, only with the Pointer Scheme
+type PointerEvent struct {
+	Pos geometry.Point // Pos of the pointer
+}
+
+// PointerEventType is the reflect.Type of PointerEvent
+var PointerEventType = reflect.TypeOf(PointerEvent{})
+
+// ReloadEvent is a signal to reload the Mapping from the settings
+type ReloadEvent struct{}
+
+// ReloadEventType is the reflect.Type of ReloadEvent
+var ReloadEventType = reflect.TypeOf(ReloadEvent{})
+
+type inputSystem struct {
+	eng     *gosge.Engine
+	mapping Mapping        // current mapping
+	axis    geometry.Point // last axis that we have signal
+	aim     float32        // last aim that we have signal
+}
+
+// load the system
+func (is *inputSystem) load(eng *gosge.Engine) error {
+	world := eng.World()
+
+	is.mapping = Load(eng.GetSettings())
+
+	// listen to keys
+	world.AddListener(is.keyListener, events.TYPE.KeyUpEvent, events.TYPE.KeyDownEvent)
+
+	// listen to gamepad buttons
+	world.AddListener(is.gamepadListener, events.TYPE.GamePadButtonUpEvent, events.TYPE.GamePadButtonDownEvent)
+
+	// listen to gamepad sticks
+	world.AddListener(is.stickListener, events.TYPE.GamePadStickMoveEvent)
+
+	// listen to the mouse
+	world.AddListener(is.mouseListener, events.TYPE.MouseMoveEvent, events.TYPE.MouseDownEvent, events.TYPE.MouseUpEvent)
+
+	// listen to reloads
+	world.AddListener(is.reloadListener, ReloadEventType)
+
+	return nil
+}
+
+// translate keys into actions
+func (is *inputSystem) keyListener(world *goecs.World, signal interface{}, _ float32) error {
+	switch e := signal.(type) {
+	case events.KeyDownEvent:
+		if a, ok := is.mapping.KeyAction(e.Key); ok {
+			world.Signal(ActionEvent{Action: a, Pressed: true})
+		}
+	case events.KeyUpEvent:
+		if a, ok := is.mapping.KeyAction(e.Key); ok {
+			world.Signal(ActionEvent{Action: a, Pressed: false})
+		}
+	}
+	return nil
+}
+
+// translate gamepad buttons into actions
+func (is *inputSystem) gamepadListener(world *goecs.World, signal interface{}, _ float32) error {
+	switch e := signal.(type) {
+	case events.GamePadButtonDownEvent:
+		if a, ok := is.mapping.ButtonAction(e.Button); ok {
+			world.Signal(ActionEvent{Action: a, Pressed: true})
+		}
+	case events.GamePadButtonUpEvent:
+		if a, ok := is.mapping.ButtonAction(e.Button); ok {
+			world.Signal(ActionEvent{Action: a, Pressed: false})
+		}
+	}
+	return nil
+}
+
+// apply the dead zone to the sticks
+func (is *inputSystem) stickListener(world *goecs.World, signal interface{}, _ float32) error {
+	switch e := signal.(type) {
+	case events.GamePadStickMoveEvent:
+		// the right stick aims
+		if e.Stick == device.GamepadRightStick {
+			aim := is.mapping.Axis(e.Movement.Y)
+			if aim != is.aim {
+				is.aim = aim
+				world.Signal(AimEvent{Amount: aim})
+			}
+			return nil
+		}
+		axis := geometry.Point{
+			X: is.mapping.Axis(e.Movement.X),
+			Y: is.mapping.Axis(e.Movement.Y),
+		}
+		if axis != is.axis {
+			is.axis = axis
+			world.Signal(AxisEvent{Movement: axis})
+		}
+	}
+	return nil
+}
+
+// with the pointer scheme the mouse steer, and the left button fires
+func (is *inputSystem) mouseListener(world *goecs.World, signal interface{}, _ float32) error {
+	if is.mapping.Scheme != Pointer {
+		return nil
+	}
+	switch e := signal.(type) {
+	case events.MouseMoveEvent:
+		world.Signal(PointerEvent{Pos: e.Point})
+	case events.MouseDownEvent:
+		if e.MouseButton == device.MouseLeftButton {
+			world.Signal(ActionEvent{Action: Fire, Pressed: true})
+		}
+	case events.MouseUpEvent:
+		if e.MouseButton == device.MouseLeftButton {
+			world.Signal(ActionEvent{Action: Fire, Pressed: false})
+		}
+	}
+	return nil
+}
+
+// reload the mapping
+func (is *inputSystem) reloadListener(_ *goecs.World, signal interface{}, _ float32) error {
+	switch signal.(type) {
+	case ReloadEvent:
+		is.mapping = Load(is.eng.GetSettings())
+	}
+	return nil
+}
+
+// System create the input system, that translate devices into actions
+func System(engine *gosge.Engine) error {
+	is := inputSystem{
+		eng: engine,
+	}
+	return is.load(engine)
+}
@@ -0,0 +1,306 @@
+/*
+ * Copyright (c) 2020 Juan Medina.
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package input
+
+import (
+	"fmt"
+	"github.com/juan-medina/gosge/components/device"
+	"github.com/juan-medina/gosge/options"
+	"github.com/juan-medina/mesh2prod/game/constants"
+)
+
+// Action is a game action that could be bind to a key and a gamepad button
+type Action int
+
+// actions
+const (
+	MoveUp       = Action(iota) // MoveUp the plane
+	MoveDown                    // MoveDown the plane
+	Fire                        // Fire a bullet
+	Pause                       // Pause the game
+	MoveLeft                    // MoveLeft the plane
+	MoveRight                   // MoveRight the plane
+	Boost                       // Boost the scroll speed
+	Brake                       // Brake the scroll speed
+	SwitchWeapon                // SwitchWeapon cycle to the next weapon
+)
+
+// Scheme is how the plane is steered
+type Scheme int
+
+// schemes
+const (
+	Buttons = Scheme(iota) // Buttons steer the plane with keys, gamepad buttons and sticks
+	Pointer                // Pointer steer the plane toward the mouse, and click fires
+)
+
+// SchemeNames is our schemes names
+var SchemeNames = map[Scheme]string{
+	Buttons: "Buttons",
+	Pointer: "Pointer",
+}
+
+// Targeting is how we aim at the blocks
+type Targeting int
+
+// targeting modes
+const (
+	Lock      = Targeting(iota) // Lock to the nearest block in the plane row
+	SmartLock                   // SmartLock to the block that will clear the largest area
+	FreeAim                     // FreeAim with a reticle moved with the right stick
+	Manual                      // Manual fire straight ahead without any assist
+	totalTargeting
+)
+
+// TargetingNames is our targeting modes names
+var TargetingNames = map[Targeting]string{
+	Lock:      "Lock",
+	SmartLock: "Smart Lock",
+	FreeAim:   "Free Aim",
+	Manual:    "Manual",
+}
+
+// Next returns the next Targeting mode, wrapping to the first one
+func (t Targeting) Next() Targeting {
+	return (t + 1) % totalTargeting
+}
+
+// Binding is the key and gamepad button for an Action
+type Binding struct {
+	Key    device.Key           // Key for the action
+	Button device.GamepadButton // Button for the action
+}
+
+var (
+	// Actions is our actions in display order
+	Actions = []Action{MoveUp, MoveDown, MoveLeft, MoveRight, Boost, Brake, Fire, SwitchWeapon, Pause}
+
+	// ActionNames is our actions names
+	ActionNames = map[Action]string{
+		MoveUp:       "Move Up",
+		MoveDown:     "Move Down",
+		MoveLeft:     "Move Left",
+		MoveRight:    "Move Right",
+		Boost:        "Boost",
+		Brake:        "Brake",
+		Fire:         "Fire",
+		SwitchWeapon: "Switch Weapon",
+		Pause:        "Pause",
+	}
+
+	// settings names for each action
+	actionSettings = map[Action]string{
+		MoveUp:       "move_up",
+		MoveDown:     "move_down",
+		MoveLeft:     "move_left",
+		MoveRight:    "move_right",
+		Boost:        "boost",
+		Brake:        "brake",
+		Fire:         "fire",
+		SwitchWeapon: "switch_weapon",
+		Pause:        "pause",
+	}
+
+	// DefaultBindings is the default Binding for each Action
+	DefaultBindings = map[Action]Binding{
+		MoveUp:       {Key: device.KeyUp, Button: device.GamepadUp},
+		MoveDown:     {Key: device.KeyDown, Button: device.GamepadDown},
+		MoveLeft:     {Key: device.KeyLeft, Button: device.GamepadLeft},
+		MoveRight:    {Key: device.KeyRight, Button: device.GamepadRight},
+		Boost:        {Key: device.KeyCtrlLeft, Button: device.GamepadRightTrigger1},
+		Brake:        {Key: device.KeyAltLeft, Button: device.GamepadLeftTrigger1},
+		Fire:         {Key: device.KeySpace, Button: device.GamepadButton3},
+		SwitchWeapon: {Key: device.KeyAltRight, Button: device.GamepadButton1},
+		Pause:        {Key: device.KeyEscape, Button: device.GamepadStart},
+	}
+
+	keyNames = map[device.Key]string{
+		device.KeyLeft:      "Left",
+		device.KeyRight:     "Right",
+		device.KeyUp:        "Up",
+		device.KeyDown:      "Down",
+		device.KeySpace:     "Space",
+		device.KeyAltLeft:   "Left Alt",
+		device.KeyCtrlLeft:  "Left Ctrl",
+		device.KeyAltRight:  "Right Alt",
+		device.KeyCtrlRight: "Right Ctrl",
+		device.KeyEscape:    "Escape",
+		device.KeyReturn:    "Return",
+	}
+
+	buttonNames = map[device.GamepadButton]string{
+		device.GamepadUp:            "DPad Up",
+		device.GamepadRight:         "DPad Right",
+		device.GamepadDown:          "DPad Down",
+		device.GamepadLeft:          "DPad Left",
+		device.GamepadButton1:       "Y",
+		device.GamepadButton2:       "X",
+		device.GamepadButton3:       "A",
+		device.GamepadButton4:       "B",
+		device.GamepadLeftTrigger1:  "L1",
+		device.GamepadLeftTrigger2:  "L2",
+		device.GamepadRightTrigger1: "R1",
+		device.GamepadRightTrigger2: "R2",
+		device.GamepadSelect:        "Select",
+		device.GamepadSpecial:       "Special",
+		device.GamepadStart:         "Start",
+		device.GamepadLeftThumb:     "Left Thumb",
+		device.GamepadRightThumb:    "Right Thumb",
+	}
+)
+
+// Mapping is the Binding for each Action, the gamepad sticks dead zone, the steering Scheme and the Targeting mode
+type Mapping struct {
+	Bindings  map[Action]Binding // Bindings for each Action
+	DeadZone  float32            // DeadZone for the gamepad sticks, from 0 to 1
+	Scheme    Scheme             // Scheme to steer the plane
+	Targeting Targeting          // Targeting mode
+}
+
+// Default returns the default Mapping
+func Default() Mapping {
+	m := Mapping{
+		Bindings:  make(map[Action]Binding),
+		DeadZone:  constants.DefaultDeadZone,
+		Scheme:    Buttons,
+		Targeting: Lock,
+	}
+	for _, a := range Actions {
+		m.Bindings[a] = DefaultBindings[a]
+	}
+	return m
+}
+
+// Load the Mapping from the settings
+func Load(settings options.Settings) Mapping {
+	m := Default()
+	for _, a := range Actions {
+		b := m.Bindings[a]
+		b.Key = device.Key(settings.GetIn32(keyConfig(a), int32(b.Key)))
+		b.Button = device.GamepadButton(settings.GetIn32(buttonConfig(a), int32(b.Button)))
+		m.Bindings[a] = b
+	}
+	m.DeadZone = settings.GetFloat32(constants.InputDeadZoneConfig, m.DeadZone)
+	m.Scheme = Scheme(settings.GetIn32(constants.InputSchemeConfig, int32(m.Scheme)))
+	m.Targeting = Targeting(settings.GetIn32(constants.InputTargetingConfig, int32(m.Targeting)))
+	return m
+}
+
+// Save the Mapping in the settings
+func (m Mapping) Save(settings options.Settings) {
+	for _, a := range Actions {
+		b := m.Bindings[a]
+		settings.SetInt32(keyConfig(a), int32(b.Key))
+		settings.SetInt32(buttonConfig(a), int32(b.Button))
+	}
+	settings.SetFloat32(constants.InputDeadZoneConfig, m.DeadZone)
+	settings.SetInt32(constants.InputSchemeConfig, int32(m.Scheme))
+	settings.SetInt32(constants.InputTargetingConfig, int32(m.Targeting))
+}
+
+// KeyAction returns the Action bind to a key, and if there is any
+func (m Mapping) KeyAction(key device.Key) (Action, bool) {
+	for _, a := range Actions {
+		if m.Bindings[a].Key == key {
+			return a, true
+		}
+	}
+	return 0, false
+}
+
+// ButtonAction returns the Action bind to a gamepad button, and if there is any
+func (m Mapping) ButtonAction(button device.GamepadButton) (Action, bool) {
+	for _, a := range Actions {
+		if m.Bindings[a].Button == button {
+			return a, true
+		}
+	}
+	return 0, false
+}
+
+// BindKey to an Action, if the key was bind to other Action they will swap keys
+func (m *Mapping) BindKey(action Action, key device.Key) {
+	if other, ok := m.KeyAction(key); ok && other != action {
+		ob := m.Bindings[other]
+		ob.Key = m.Bindings[action].Key
+		m.Bindings[other] = ob
+	}
+	b := m.Bindings[action]
+	b.Key = key
+	m.Bindings[action] = b
+}
+
+// BindButton to an Action, if the button was bind to other Action they will swap buttons
+func (m *Mapping) BindButton(action Action, button device.GamepadButton) {
+	if other, ok := m.ButtonAction(button); ok && other != action {
+		ob := m.Bindings[other]
+		ob.Button = m.Bindings[action].Button
+		m.Bindings[other] = ob
+	}
+	b := m.Bindings[action]
+	b.Button = button
+	m.Bindings[action] = b
+}
+
+// Axis returns a stick axis value after applying the dead zone, scaled so it still goes from -1 to 1
+func (m Mapping) Axis(value float32) float32 {
+	if m.DeadZone >= 1 {
+		return 0
+	}
+	if value > -m.DeadZone && value < m.DeadZone {
+		return 0
+	}
+	if value > 0 {
+		return (value - m.DeadZone) / (1 - m.DeadZone)
+	}
+	return (value + m.DeadZone) / (1 - m.DeadZone)
+}
+
+// KeyName returns the display name of a key
+func KeyName(key device.Key) string {
+	if name, ok := keyNames[key]; ok {
+		return name
+	}
+	if key >= device.KeyF1 && key <= device.KeyF12 {
+		return fmt.Sprintf("F%d", int(key-device.KeyF1)+1)
+	}
+	return "None"
+}
+
+// ButtonName returns the display name of a gamepad button
+func ButtonName(button device.GamepadButton) string {
+	if name, ok := buttonNames[button]; ok {
+		return name
+	}
+	return "None"
+}
+
+// keyConfig returns the setting name for the key of an Action
+func keyConfig(a Action) string {
+	return fmt.Sprintf(constants.InputKeyConfig, actionSettings[a])
+}
+
+// buttonConfig returns the setting name for the gamepad button of an Action
+func buttonConfig(a Action) string {
+	return fmt.Sprintf(constants.InputButtonConfig, actionSettings[a])
+}
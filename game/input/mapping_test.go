@@ -0,0 +1,120 @@
+/*
+ * Copyright (c) 2020 Juan Medina.
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package input
+
+import (
+	"fmt"
+	"github.com/juan-medina/gosge/components/device"
+	"testing"
+)
+
+func TestMapping_BindKey(t *testing.T) {
+	m := Default()
+
+	m.BindKey(Fire, device.KeyCtrlRight)
+
+	if got := m.Bindings[Fire].Key; got != device.KeyCtrlRight {
+		t.Fatalf("bind key error, got %v, expect %v", got, device.KeyCtrlRight)
+	}
+
+	// binding a used key swap them
+	m.BindKey(MoveUp, device.KeyDown)
+
+	if got := m.Bindings[MoveUp].Key; got != device.KeyDown {
+		t.Fatalf("bind key error, got %v, expect %v", got, device.KeyDown)
+	}
+
+	if got := m.Bindings[MoveDown].Key; got != device.KeyUp {
+		t.Fatalf("bind key swap error, got %v, expect %v", got, device.KeyUp)
+	}
+}
+
+func TestMapping_BindButton(t *testing.T) {
+	m := Default()
+
+	m.BindButton(Pause, device.GamepadButton3)
+
+	if got := m.Bindings[Pause].Button; got != device.GamepadButton3 {
+		t.Fatalf("bind button error, got %v, expect %v", got, device.GamepadButton3)
+	}
+
+	if got := m.Bindings[Fire].Button; got != device.GamepadStart {
+		t.Fatalf("bind button swap error, got %v, expect %v", got, device.GamepadStart)
+	}
+
+	if got, ok := m.ButtonAction(device.GamepadButton3); !ok || got != Pause {
+		t.Fatalf("button action error, got %v, expect %v", got, Pause)
+	}
+}
+
+func TestMapping_Axis(t *testing.T) {
+	type tc struct {
+		deadZone float32
+		given    float32
+		expect   float32
+	}
+
+	cases := []tc{
+		{deadZone: 0.2, given: 0.1, expect: 0},
+		{deadZone: 0.2, given: -0.19, expect: 0},
+		{deadZone: 0.2, given: 1, expect: 1},
+		{deadZone: 0.2, given: -1, expect: -1},
+		{deadZone: 0.2, given: 0.6, expect: 0.5},
+		{deadZone: 0.2, given: -0.6, expect: -0.5},
+		{deadZone: 0, given: 0.3, expect: 0.3},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("case %d", i+1), func(t *testing.T) {
+			m := Mapping{DeadZone: c.deadZone}
+			got := m.Axis(c.given)
+
+			diff := got - c.expect
+			if diff < -0.0001 || diff > 0.0001 {
+				t.Fatalf("axis error, got %v, expect %v", got, c.expect)
+			}
+		})
+	}
+}
+
+func TestTargeting_Next(t *testing.T) {
+	type tc struct {
+		given  Targeting
+		expect Targeting
+	}
+
+	cases := []tc{
+		{given: Lock, expect: SmartLock},
+		{given: SmartLock, expect: FreeAim},
+		{given: FreeAim, expect: Manual},
+		{given: Manual, expect: Lock},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("case %d", i+1), func(t *testing.T) {
+			if got := c.given.Next(); got != c.expect {
+				t.Fatalf("next targeting error, got %v, expect %v", got, c.expect)
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+/*
+ * Copyright (c) 2020 Juan Medina.
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package level
+
+import (
+	"fmt"
+	"github.com/juan-medina/gosge/options"
+	"github.com/juan-medina/mesh2prod/game/constants"
+)
+
+// Environment is a deployment environment, each cloud has a level per environment
+type Environment int
+
+// environments
+const (
+	Dev     = Environment(iota) // Dev environment
+	Test                        // Test environment
+	Staging                     // Staging environment
+	Prod                        // Prod environment
+)
+
+var (
+	// Environments is our environments in campaign order
+	Environments = []Environment{Dev, Test, Staging, Prod}
+
+	// EnvironmentNames is our environments names
+	EnvironmentNames = map[Environment]string{
+		Dev:     "dev",
+		Test:    "test",
+		Staging: "staging",
+		Prod:    "prod",
+	}
+)
+
+// rules for an environment
+type rules struct {
+	timeFactor     float32 // factor of the cloud time limit, 0 for no limit
+	planeIntegrity int     // number of blocks that the plane could hit before been destroyed
+	meshIntegrity  int     // number of blocks that the mesh could hit before been destroyed
+	density        int     // additional pieces per each group of pieces in the map
+	services       int     // number of services in the convoy
+	bossPhases     int     // number of phases of the boss before production
+}
+
+var (
+	envRules = map[Environment]rules{
+		Dev:     {timeFactor: 0, planeIntegrity: 30, meshIntegrity: 60, density: 0, services: 1, bossPhases: 1},
+		Test:    {timeFactor: 1.2, planeIntegrity: 25, meshIntegrity: 50, density: 1, services: 2, bossPhases: 2},
+		Staging: {timeFactor: 1, planeIntegrity: 20, meshIntegrity: 40, density: 2, services: 2, bossPhases: 2},
+		Prod:    {timeFactor: 0.9, planeIntegrity: 15, meshIntegrity: 30, density: 3, services: 3, bossPhases: 3},
+	}
+)
+
+// Level is a campaign level and its rules
+type Level struct {
+	Cloud          constants.CloudSize // Cloud is the cloud size of this level
+	Environment    Environment         // Environment of this level
+	Length         int                 // Length of the map in blocks
+	TimeLimit      float32             // TimeLimit in seconds to reach production, 0 for no limit
+	PlaneIntegrity int                 // PlaneIntegrity is the number of blocks that the plane could hit
+	MeshIntegrity  int                 // MeshIntegrity is the number of blocks that each service in the convoy could hit
+	Density        int                 // Density is the additional pieces per each group of pieces in the map
+	Services       int                 // Services is the number of payloads in the convoy
+	BossPhases     int                 // BossPhases is the number of phases of the Monolith before production
+}
+
+// Get the Level for a cloud size and environment
+func Get(cs constants.CloudSize, env Environment) Level {
+	r := envRules[env]
+	return Level{
+		Cloud:          cs,
+		Environment:    env,
+		Length:         constants.CloudSizes[cs],
+		TimeLimit:      constants.CloudTimeLimits[cs] * r.timeFactor,
+		PlaneIntegrity: r.planeIntegrity,
+		MeshIntegrity:  r.meshIntegrity,
+		Density:        r.density,
+		Services:       r.services,
+		BossPhases:     r.bossPhases,
+	}
+}
+
+// Current returns the Level selected in the settings
+func Current(settings options.Settings) Level {
+	cs := constants.CloudSize(settings.GetIn32(constants.CloudSizeConfig, int32(constants.StartupCloud)))
+	env := Environment(settings.GetIn32(constants.CampaignLevelConfig, int32(Dev)))
+	return Get(cs, env)
+}
+
+// Select a Level in the settings
+func Select(settings options.Settings, lvl Level) {
+	settings.SetInt32(constants.CloudSizeConfig, int32(lvl.Cloud))
+	settings.SetInt32(constants.CampaignLevelConfig, int32(lvl.Environment))
+}
+
+// Name returns the display name of the Level
+func (l Level) Name() string {
+	return fmt.Sprintf("%s cloud : %s", constants.CloudNames[l.Cloud], EnvironmentNames[l.Environment])
+}
+
+// Key returns a unique key for the Level, to be use in settings
+func (l Level) Key() string {
+	return fmt.Sprintf("%s_%s", constants.CloudNames[l.Cloud], EnvironmentNames[l.Environment])
+}
+
+// File returns the data file of the Level
+func (l Level) File() string {
+	return fmt.Sprintf(constants.LevelFile, EnvironmentNames[l.Environment])
+}
+
+// WavesFile returns the enemy waves file of the Level
+func (l Level) WavesFile() string {
+	return fmt.Sprintf(constants.WavesFile, EnvironmentNames[l.Environment])
+}
+
+// Next returns the next Level in the campaign and if exist
+func (l Level) Next() (Level, bool) {
+	if l.Environment == Prod {
+		return Level{}, false
+	}
+	return Get(l.Cloud, l.Environment+1), true
+}
+
+// Unlocked returns if the Level has been unlocked in the campaign
+func (l Level) Unlocked(settings options.Settings) bool {
+	return l.Environment <= progress(settings, l.Cloud)
+}
+
+// Unlock the next Level in the campaign, if any
+func (l Level) Unlock(settings options.Settings) {
+	if next, ok := l.Next(); ok {
+		if !next.Unlocked(settings) {
+			settings.SetInt32(progressConfig(l.Cloud), int32(next.Environment))
+		}
+	}
+}
+
+// progress returns the last Environment unlocked for a cloud size
+func progress(settings options.Settings, cs constants.CloudSize) Environment {
+	return Environment(settings.GetIn32(progressConfig(cs), int32(Dev)))
+}
+
+// progressConfig returns the setting name for the progress of a cloud size
+func progressConfig(cs constants.CloudSize) string {
+	return fmt.Sprintf(constants.CampaignProgressConfig, constants.CloudNames[cs])
+}
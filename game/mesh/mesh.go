@@ -31,35 +31,82 @@ import (
 	"github.com/juan-medina/gosge/components/effects"
 	"github.com/juan-medina/gosge/components/geometry"
 	"github.com/juan-medina/gosge/components/shapes"
+	"github.com/juan-medina/gosge/components/sprite"
 	"github.com/juan-medina/mesh2prod/game/component"
-	"github.com/juan-medina/mesh2prod/game/constants"
 	"github.com/juan-medina/mesh2prod/game/movement"
+	"github.com/juan-medina/mesh2prod/game/pause"
 	"github.com/juan-medina/mesh2prod/game/plane"
+	"github.com/juan-medina/mesh2prod/game/rope"
+	"github.com/juan-medina/mesh2prod/game/skin"
+	"github.com/juan-medina/mesh2prod/game/upgrade"
 	"github.com/juan-medina/mesh2prod/game/winning"
+	"math"
 )
 
 const (
 	animSpeedSlow    = 0.65         // animation slow speed
-	meshSpriteAnim   = "box%d.png"  // the mesh sprite
 	meshScale        = 0.5          // mesh scale
 	meshX            = 10           // mesh scale
-	meshSpeed        = float32(200) // mesh speed
+	followSpeed      = float32(200) // speed of the services that follow the one in front
 	topMeshSpeed     = float32(250) // top mesh speed
-	joinShiftX       = 5            // shift X for the joint
-	joinShiftYTop    = 130          // shift Y for the top joint
-	joinShiftYBottom = 170          // shift Y for the bottom joint
 	lineThickness    = 5            // the line thickness
 	meshScrollSpeedX = 25           // mesh scroll x (match block scroll)
+	ropeSegments     = 12           // rope segments
+	ropeSlack        = 1.1          // rope length over the starting distance from the plane
+	ropeStiffness    = 0.8          // rope stiffness, from 0 to 1
+	ropeGravity      = 600          // gravity that make the rope sag
+	ropeTension      = 30           // mesh acceleration for each point that the rope stretch
+	ropeTolerance    = 0.02         // rope stretch that does not pull, as a part of the rope length
+	meshDrag         = 3            // how fast the mesh lose speed
+	bridleLength     = 40           // distance from the mesh joints to where the rope is tied
+	snagDamageDelay  = 1            // seconds between mesh damage while the rope is snagged
+	serviceGap       = 60           // horizontal gap between the services in the convoy
+	serviceDelay     = 0.35         // delay in seconds for a service to follow the one in front
 )
 
+// a vertical position at a given time
+type trailPoint struct {
+	time float32
+	y    float32
+}
+
+// a service in the convoy
+type service struct {
+	ent    *goecs.Entity    // the payload
+	line   [2]*goecs.Entity // bridle lines
+	tether *goecs.Entity    // tether to the service in front, the lead use the rope
+	trail  []trailPoint     // recent vertical positions, so the service behind could follow
+	lost   bool             // has been lost
+}
+
+// the vertical position that the service had at a time
+func (s service) delayedY(at float32) float32 {
+	for i := len(s.trail) - 1; i >= 0; i-- {
+		if s.trail[i].time <= at {
+			return s.trail[i].y
+		}
+	}
+	return s.trail[0].y
+}
+
 type meshSystem struct {
+	pause.State
 	gs       geometry.Scale
 	dr       geometry.Size
-	mesh     *goecs.Entity
-	planePos geometry.Point
-	line     [2]*goecs.Entity
 	size     geometry.Size
 	end      bool
+	tension  float32         // rope tension, with the plane speed upgrade applied
+	topSpeed float32         // top mesh speed, with the plane speed upgrade applied
+	skin     skin.Payload    // mesh skin
+	eng      *gosge.Engine   // the game engine
+	joint    geometry.Point  // plane joint position
+	rope     *rope.Rope      // the rope to the plane, created with the first plane position
+	ropeLine []*goecs.Entity // rope segments
+	ropeLead int             // service tied to the rope
+	velY     float32         // lead vertical speed
+	snagTime float32         // time to the next damage while the rope is snagged
+	services []*service      // the services in the convoy, the first is the lead
+	time     float32         // time since the level start
 }
 
 // add the background
@@ -69,31 +116,72 @@ func (ms *meshSystem) load(eng *gosge.Engine) error {
 	// get the ECS world
 	world := eng.World()
 
+	// the mesh need to keep up with the plane
+	factor := upgrade.PlaneSpeed.Value(eng.GetSettings())
+	ms.tension = ropeTension * factor
+	ms.topSpeed = topMeshSpeed * factor
+
 	// get the size of the mesh
-	if ms.size, err = eng.GetSpriteSize(constants.SpriteSheet, fmt.Sprintf(meshSpriteAnim, 1)); err != nil {
+	if ms.size, err = eng.GetSpriteSize(ms.skin.Sheet, fmt.Sprintf(ms.skin.Base, 1)); err != nil {
 		return err
 	}
 
+	// add the services, the lead is in front
+	for i, s := range ms.services {
+		behind := float32(len(ms.services) - 1 - i)
+		ms.addService(world, s, i, behind*(ms.size.Width*meshScale+serviceGap))
+	}
+
+	// create the rope segments
+	ms.ropeLine = make([]*goecs.Entity, ropeSegments)
+	for i := range ms.ropeLine {
+		ms.ropeLine[i] = world.AddEntity(
+			shapes.Line{
+				To:        geometry.Point{},
+				Thickness: lineThickness * ms.gs.Max,
+			},
+			geometry.Point{},
+			effects.Layer{Depth: 0.5},
+			color.Gray,
+		)
+	}
+
+	// add the follow system
+	world.AddSystem(ms.followSystem)
+
+	// listen to plane changes
+	world.AddListener(ms.planeChanges, plane.PositionChangeEventType)
+
+	// keep track of the pause
+	ms.Track(world)
+
+	// listen to level events
+	world.AddListener(ms.levelEvents, winning.LevelEndEventType, winning.ServiceLostEventType)
+
+	return nil
+}
+
+// add a service entity and its lines, with an horizontal offset
+func (ms *meshSystem) addService(world *goecs.World, s *service, index int, offset float32) {
 	// calculate halve of the height
 	halveHeight := (ms.size.Height / 2) * meshScale
 
-	// add the mesh
-	ms.mesh = world.AddEntity(
+	s.ent = world.AddEntity(
 		animation.Animation{
 			Sequences: map[string]animation.Sequence{
 				"flying": {
-					Sheet:  constants.SpriteSheet,
-					Base:   meshSpriteAnim,
+					Sheet:  ms.skin.Sheet,
+					Base:   ms.skin.Base,
 					Scale:  ms.gs.Max * meshScale,
-					Frames: 2,
-					Delay:  0.065,
+					Frames: ms.skin.Frames,
+					Delay:  ms.skin.Delay,
 				},
 			},
 			Current: "flying",
 			Speed:   animSpeedSlow,
 		},
 		geometry.Point{
-			X: (ms.size.Width / 2 * meshScale * ms.gs.Max) + meshX*ms.gs.Max,
+			X: (ms.size.Width / 2 * meshScale * ms.gs.Max) + (meshX+offset)*ms.gs.Max,
 			Y: ms.dr.Height / 2 * ms.gs.Max,
 		},
 		movement.Movement{
@@ -112,117 +200,295 @@ func (ms *meshSystem) load(eng *gosge.Engine) error {
 				Y: (ms.dr.Height - halveHeight) * ms.gs.Max,
 			},
 		},
-		component.Mesh{},
+		ms.skin.Color(),
+		component.Mesh{Service: index},
 		effects.Layer{Depth: 0},
 	)
 
-	// create the two lines
+	// create the two lines, and the tether
 	for ln := 0; ln < 2; ln++ {
-		ms.line[ln] = world.AddEntity(
-			shapes.Line{
-				To:        geometry.Point{},
-				Thickness: lineThickness * ms.gs.Max,
-			},
-			geometry.Point{},
-			effects.Layer{Depth: 0.5},
-			color.Gray,
-		)
+		s.line[ln] = ms.addLine(world)
 	}
+	if index > 0 {
+		s.tether = ms.addLine(world)
+	}
+}
 
-	// add the follow system
-	world.AddSystem(ms.followSystem)
-
-	// listen to plane changes
-	world.AddListener(ms.planeChanges, plane.PositionChangeEventType)
+// add a line entity
+func (ms meshSystem) addLine(world *goecs.World) *goecs.Entity {
+	return world.AddEntity(
+		shapes.Line{
+			To:        geometry.Point{},
+			Thickness: lineThickness * ms.gs.Max,
+		},
+		geometry.Point{},
+		effects.Layer{Depth: 0.5},
+		color.Gray,
+	)
+}
 
-	// listen to level events
-	world.AddListener(ms.levelEvents, winning.LevelEndEventType)
+// the first service that has not been lost, or -1 if all are lost
+func (ms meshSystem) lead() int {
+	for i, s := range ms.services {
+		if !s.lost {
+			return i
+		}
+	}
+	return -1
+}
 
+// the service in front of other that has not been lost
+func (ms meshSystem) front(index int) *service {
+	for i := index - 1; i >= 0; i-- {
+		if !ms.services[i].lost {
+			return ms.services[i]
+		}
+	}
 	return nil
 }
 
-// follow system
-func (ms *meshSystem) followSystem(_ *goecs.World, delta float32) error {
-	if ms.end {
+// follow system, the lead is pulled by the rope and the rest follow the one in front
+func (ms *meshSystem) followSystem(world *goecs.World, delta float32) error {
+	if ms.end || ms.IsPaused() {
 		return nil
 	}
-	// get mesh component
-	meshPos := geometry.Get.Point(ms.mesh)
-	mov := ms.mesh.Get(movement.Type).(movement.Movement)
+
+	ms.time += delta
+
+	lead := ms.lead()
+	for i, s := range ms.services {
+		if s.lost {
+			continue
+		}
+
+		// get mesh component
+		meshPos := geometry.Get.Point(s.ent)
+
+		// keep the recent positions
+		s.trail = append(s.trail, trailPoint{time: ms.time, y: meshPos.Y})
+		for len(s.trail) > 1 && s.trail[1].time < ms.time-serviceDelay {
+			s.trail = s.trail[1:]
+		}
+
+		// we will calculate the line from position
+		var linePos geometry.Point
+
+		// calculate X, the same for both lines
+		linePos.X = meshPos.X + (((ms.size.Width / 2) - ms.skin.Joint) * meshScale * ms.gs.Max)
+
+		// the rope or tether is tied in front of the mesh
+		tie := geometry.Point{
+			X: linePos.X + bridleLength*ms.gs.Max,
+			Y: meshPos.Y,
+		}
+
+		// top line
+		linePos.Y = meshPos.Y - (ms.skin.JointTop * meshScale * ms.gs.Max)
+		ms.setLine(s.line[0], linePos, tie)
+
+		// bottom line
+		linePos.Y = meshPos.Y + (ms.skin.JointBottom * meshScale * ms.gs.Max)
+		ms.setLine(s.line[1], linePos, tie)
+
+		if i == lead {
+			ms.pullLead(world, s, i, tie, delta)
+		} else {
+			ms.follow(s, i, meshPos, tie, delta)
+		}
+	}
+
+	return nil
+}
+
+// the lead is pulled by the rope
+func (ms *meshSystem) pullLead(world *goecs.World, s *service, index int, tie geometry.Point, delta float32) {
+	// until we know where the plane is
+	if ms.joint == (geometry.Point{}) {
+		return
+	}
+
+	// create the rope, with some slack, a new lead get a new rope
+	if ms.rope == nil || ms.ropeLead != index {
+		dx := ms.joint.X - tie.X
+		dy := ms.joint.Y - tie.Y
+		length := float32(math.Sqrt(float64(dx*dx+dy*dy))) * ropeSlack
+		ms.rope = rope.New(ms.joint, tie, ropeSegments, length, ropeStiffness, ropeGravity*ms.gs.Max)
+		ms.ropeLead = index
+	}
+
+	// simulate the rope, it could snag on blocks
+	snags := ms.rope.Update(ms.joint, tie, delta, ms.blocked(world))
+	ms.updateSnags(world, snags, delta)
+
+	// draw the rope
+	for i, line := range ms.ropeLine {
+		ms.setLine(line, ms.rope.Points[i], ms.rope.Points[i+1])
+	}
+
+	// a stretched rope pull the mesh
+	if stretch := ms.rope.Stretch() - ms.rope.Length*ropeTolerance; stretch > 0 {
+		ms.velY += ms.rope.Pull().Y * (stretch / ms.gs.Max) * ms.tension * delta
+	}
+	ms.velY -= ms.velY * meshDrag * delta
+
+	// clamp speed
+	if ms.velY > ms.topSpeed {
+		ms.velY = ms.topSpeed
+	} else if ms.velY < -ms.topSpeed {
+		ms.velY = -ms.topSpeed
+	}
+
+	// update the mesh Movement
+	mov := s.ent.Get(movement.Type).(movement.Movement)
+	mov.Amount.Y = ms.velY
+	s.ent.Set(mov)
+}
+
+// a service follow where the one in front was a moment ago, tethered to it
+func (ms *meshSystem) follow(s *service, index int, meshPos, tie geometry.Point, delta float32) {
+	front := ms.front(index)
+	frontPos := geometry.Get.Point(front.ent)
+
+	// the tether goes to the back of the service in front
+	ms.setLine(s.tether, tie, geometry.Point{
+		X: frontPos.X - (ms.size.Width / 2 * meshScale * ms.gs.Max),
+		Y: frontPos.Y,
+	})
 
 	// calculate difference
-	diffY := ms.planePos.Y - meshPos.Y
+	diffY := front.delayedY(ms.time-serviceDelay) - meshPos.Y
 
 	// increase Movement up or down
-	mov.Amount.Y = diffY * meshSpeed * ms.gs.Max * delta
+	mov := s.ent.Get(movement.Type).(movement.Movement)
+	mov.Amount.Y = diffY * followSpeed * ms.gs.Max * delta
 
 	// clamp speed
-	if mov.Amount.Y > topMeshSpeed {
-		mov.Amount.Y = topMeshSpeed
-	} else if mov.Amount.Y < -topMeshSpeed {
-		mov.Amount.Y = -topMeshSpeed
+	if mov.Amount.Y > ms.topSpeed {
+		mov.Amount.Y = ms.topSpeed
+	} else if mov.Amount.Y < -ms.topSpeed {
+		mov.Amount.Y = -ms.topSpeed
 	}
 
 	// update the mesh Movement
-	ms.mesh.Set(mov)
+	s.ent.Set(mov)
+}
 
-	// we will calculate the line from position
-	var linePos geometry.Point
+// update a line from a position to other
+func (ms meshSystem) setLine(ent *goecs.Entity, from, to geometry.Point) {
+	line := shapes.Get.Line(ent)
+	line.To = to
+	ent.Set(from)
+	ent.Set(line)
+}
 
-	// calculate X, the same for both lines
-	linePos.X = meshPos.X + (((ms.size.Width / 2) - joinShiftX) * meshScale * ms.gs.Max)
+// returns a func that tell if a point is blocked by any block close to the rope
+func (ms meshSystem) blocked(world *goecs.World) func(geometry.Point) bool {
+	// the rope goes from the mesh to the plane
+	minX := ms.rope.Points[len(ms.rope.Points)-1].X - ms.rope.Length
+	maxX := ms.joint.X + ms.rope.Length
 
-	// top line
-	linePos.Y = meshPos.Y - (joinShiftYTop * meshScale * ms.gs.Max)
-	ms.line[0].Set(linePos)
+	// get the blocks close to the rope
+	blocks := make([]*goecs.Entity, 0)
+	for it := world.Iterator(component.TYPE.Block, geometry.TYPE.Point, sprite.TYPE); it != nil; it = it.Next() {
+		block := it.Value()
+		if pos := geometry.Get.Point(block); pos.X >= minX && pos.X <= maxX {
+			blocks = append(blocks, block)
+		}
+	}
 
-	// bottom line
-	linePos.Y = meshPos.Y + (joinShiftYBottom * meshScale * ms.gs.Max)
-	ms.line[1].Set(linePos)
+	return func(point geometry.Point) bool {
+		for _, block := range blocks {
+			if ms.eng.SpriteAtContains(sprite.Get(block), geometry.Get.Point(block), point) {
+				return true
+			}
+		}
+		return false
+	}
+}
 
-	return nil
+// while the rope is snagged it damage the lead
+func (ms *meshSystem) updateSnags(world *goecs.World, snags int, delta float32) {
+	if snags == 0 {
+		if ms.snagTime > 0 {
+			ms.snagTime = 0
+			for _, line := range ms.ropeLine {
+				line.Set(color.Gray)
+			}
+		}
+		return
+	}
+	if ms.snagTime -= delta; ms.snagTime <= 0 {
+		ms.snagTime = snagDamageDelay
+		world.Signal(winning.DamageMeshEvent{Service: ms.ropeLead, Blocks: 1})
+		for _, line := range ms.ropeLine {
+			line.Set(color.Red)
+		}
+	}
 }
 
-// when plane changes save it position
+// when plane changes save it joint position
 func (ms *meshSystem) planeChanges(_ *goecs.World, signal interface{}, _ float32) error {
 	if ms.end {
 		return nil
 	}
 	switch e := signal.(type) {
 	case plane.PositionChangeEvent:
-		ms.planePos = e.Pos
-		// go the lines and update the to, same for both
-		for ln := 0; ln < 2; ln++ {
-			line := shapes.Get.Line(ms.line[ln])
-			line.To = e.Joint // we will use the joint position send by the plane
-			ms.line[ln].Set(line)
-		}
+		ms.joint = e.Joint // we will use the joint position send by the plane
 	}
 	return nil
 }
 
+// remove the lines of a service
+func (ms *meshSystem) removeLines(world *goecs.World, s *service) {
+	for ln := 0; ln < 2; ln++ {
+		_ = world.Remove(s.line[ln])
+	}
+	if s.tether != nil {
+		_ = world.Remove(s.tether)
+	}
+}
+
 func (ms *meshSystem) levelEvents(world *goecs.World, signal interface{}, _ float32) error {
-	switch signal.(type) {
+	switch e := signal.(type) {
+	case winning.ServiceLostEvent:
+		if s := ms.services[e.Service]; !s.lost {
+			s.lost = true
+			ms.removeLines(world, s)
+			_ = world.Remove(s.ent)
+		}
 	case winning.LevelEndEvent:
 		ms.end = true
-		for ln := 0; ln < 2; ln++ {
-			_ = world.Remove(ms.line[ln])
-			ms.mesh.Set(movement.Movement{Amount: geometry.Point{
+		for _, line := range ms.ropeLine {
+			_ = world.Remove(line)
+		}
+		for _, s := range ms.services {
+			if s.lost {
+				continue
+			}
+			ms.removeLines(world, s)
+			s.ent.Set(movement.Movement{Amount: geometry.Point{
 				X: -meshScrollSpeedX * ms.gs.Max,
 				Y: 0,
 			}})
-			ms.mesh.Remove(movement.ConstrainType)
+			s.ent.Remove(movement.ConstrainType)
+			s.ent.Set(movement.Scroll{})
 		}
 	}
 
 	return nil
 }
 
-// System creates the mesh system
-func System(engine *gosge.Engine, gs geometry.Scale, dr geometry.Size) error {
+// System creates the mesh system, with a convoy of services
+func System(engine *gosge.Engine, gs geometry.Scale, dr geometry.Size, sk skin.Payload, services int) error {
 	bs := meshSystem{
-		gs: gs,
-		dr: dr,
+		gs:       gs,
+		dr:       dr,
+		skin:     sk,
+		eng:      engine,
+		services: make([]*service, services),
+	}
+	for i := range bs.services {
+		bs.services[i] = &service{}
 	}
 	return bs.load(engine)
 }
@@ -26,15 +26,26 @@ import (
 	"github.com/juan-medina/goecs"
 	"github.com/juan-medina/gosge"
 	"github.com/juan-medina/gosge/components/geometry"
+	"github.com/juan-medina/mesh2prod/game/pause"
+	"math"
 	"reflect"
 )
 
 type movementSystem struct {
-	gs geometry.Scale
+	pause.State
+	gs     geometry.Scale
+	scroll float32        // current scroll speed factor
+	hold   bool           // is the scroll on hold
+	drift  geometry.Point // current turbulence drift
 }
 
 // move system
-func (ms movementSystem) system(world *goecs.World, delta float32) error {
+func (ms *movementSystem) system(world *goecs.World, delta float32) error {
+	// nothing moves while paused
+	if ms.IsPaused() {
+		return nil
+	}
+
 	// move anything that has a position and Movement
 	for it := world.Iterator(geometry.TYPE.Point, Type); it != nil; it = it.Next() {
 		// get the entity
@@ -46,7 +57,19 @@ func (ms movementSystem) system(world *goecs.World, delta float32) error {
 
 		// increment position and clamp to the Min/Max
 		pos.Y += mov.Amount.Y * delta * ms.gs.Max
-		pos.X += mov.Amount.X * delta * ms.gs.Max
+		// anything that scroll with the map use the scroll speed factor
+		if ent.Contains(ScrollType) {
+			if !ms.hold {
+				pos.X += mov.Amount.X * delta * ms.gs.Max * ms.scroll
+			}
+		} else {
+			pos.X += mov.Amount.X * delta * ms.gs.Max
+		}
+
+		// anything that drift is moved by the turbulence
+		if ent.Contains(DriftType) {
+			pos = ms.drifted(pos, delta)
+		}
 
 		// if we have constrains
 		if ent.Contains(ConstrainType) {
@@ -62,6 +85,70 @@ func (ms movementSystem) system(world *goecs.World, delta float32) error {
 	return nil
 }
 
+// projectile system
+func (ms *movementSystem) projectileSystem(world *goecs.World, delta float32) error {
+	// nothing moves while paused
+	if ms.IsPaused() {
+		return nil
+	}
+
+	// move anything that has a position and is a Projectile
+	for it := world.Iterator(geometry.TYPE.Point, ProjectileType); it != nil; it = it.Next() {
+		// get the entity
+		ent := it.Value()
+
+		// get current position and Projectile
+		pos := geometry.Get.Point(ent)
+		prj := ent.Get(ProjectileType).(Projectile)
+
+		// if we have constrains we bounce off them
+		var constrain *Constrain = nil
+		if ent.Contains(ConstrainType) {
+			c := ent.Get(ConstrainType).(Constrain)
+			constrain = &c
+		}
+
+		pos, prj = prj.Step(pos, constrain, delta, ms.gs.Max)
+
+		// anything that drift is moved by the turbulence
+		if ent.Contains(DriftType) {
+			pos = ms.drifted(pos, delta)
+		}
+
+		// remove it when its life runs out
+		if prj.Life <= 0 {
+			_ = world.Remove(ent)
+			continue
+		}
+
+		// update entity
+		ent.Set(pos)
+		ent.Set(prj)
+	}
+
+	return nil
+}
+
+// returns a position moved by the turbulence drift
+func (ms movementSystem) drifted(pos geometry.Point, delta float32) geometry.Point {
+	pos.X += ms.drift.X * delta * ms.gs.Max
+	pos.Y += ms.drift.Y * delta * ms.gs.Max
+	return pos
+}
+
+// keep track of the scroll speed and the turbulence
+func (ms *movementSystem) stateListener(_ *goecs.World, signal interface{}, _ float32) error {
+	switch e := signal.(type) {
+	case ScrollSpeedEvent:
+		ms.scroll = e.Factor
+	case HoldScrollEvent:
+		ms.hold = e.Hold
+	case TurbulenceEvent:
+		ms.drift = e.Drift
+	}
+	return nil
+}
+
 // Constrain of the movement
 type Constrain struct {
 	Min geometry.Point // Min position that we could move
@@ -71,6 +158,42 @@ type Constrain struct {
 // ConstrainType is the reflect.Type of Movement
 var ConstrainType = reflect.TypeOf(Constrain{})
 
+// Scroll is a component for entities that move with the map scroll
+type Scroll struct{}
+
+// ScrollType is the reflect.Type of Scroll
+var ScrollType = reflect.TypeOf(Scroll{})
+
+// ScrollSpeedEvent is a signal to change the scroll speed
+type ScrollSpeedEvent struct {
+	Factor float32 // Factor to apply to the horizontal movement of the entities that Scroll
+}
+
+// ScrollSpeedEventType is the reflect.Type of ScrollSpeedEvent
+var ScrollSpeedEventType = reflect.TypeOf(ScrollSpeedEvent{})
+
+// HoldScrollEvent is a signal to stop, or resume, the scroll of the entities that Scroll
+type HoldScrollEvent struct {
+	Hold bool // Hold the scroll
+}
+
+// HoldScrollEventType is the reflect.Type of HoldScrollEvent
+var HoldScrollEventType = reflect.TypeOf(HoldScrollEvent{})
+
+// Drift is a component for entities that drift with the turbulence
+type Drift struct{}
+
+// DriftType is the reflect.Type of Drift
+var DriftType = reflect.TypeOf(Drift{})
+
+// TurbulenceEvent is a signal to change the turbulence drift
+type TurbulenceEvent struct {
+	Drift geometry.Point // Drift, in design units per second, of the entities that Drift
+}
+
+// TurbulenceEventType is the reflect.Type of TurbulenceEvent
+var TurbulenceEventType = reflect.TypeOf(TurbulenceEvent{})
+
 // Movement indicate how much we need to move
 type Movement struct {
 	Amount geometry.Point // Amount that we could move
@@ -79,13 +202,88 @@ type Movement struct {
 // Type is the reflect.Type of Movement
 var Type = reflect.TypeOf(Movement{})
 
+// Projectile is a component for entities that move with a velocity and a gravity, and bounce off their Constrain
+type Projectile struct {
+	Velocity geometry.Point // Velocity of the projectile
+	Gravity  float32        // Gravity that pull the projectile down
+	Bounce   float32        // Bounce is the velocity that we keep when we bounce off the Constrain
+	Life     float32        // Life in seconds, the projectile is removed when it runs out
+}
+
+// ProjectileType is the reflect.Type of Projectile
+var ProjectileType = reflect.TypeOf(Projectile{})
+
+// Step move a Projectile from a position for a delta time, bouncing off an optional Constrain,
+// an axis where the Constrain has no range is not constrained
+func (p Projectile) Step(from geometry.Point, constrain *Constrain, delta, scale float32) (geometry.Point, Projectile) {
+	p.Velocity.Y += p.Gravity * delta
+
+	pos := geometry.Point{
+		X: from.X + p.Velocity.X*delta*scale,
+		Y: from.Y + p.Velocity.Y*delta*scale,
+	}
+
+	if constrain != nil {
+		var hit bool
+		if pos.X, hit = bounce(pos.X, constrain.Min.X, constrain.Max.X); hit {
+			p.Velocity.X = -p.Velocity.X * p.Bounce
+		}
+		if pos.Y, hit = bounce(pos.Y, constrain.Min.Y, constrain.Max.Y); hit {
+			p.Velocity.Y = -p.Velocity.Y * p.Bounce
+		}
+	}
+
+	p.Life -= delta
+
+	return pos, p
+}
+
+// Ricochet a Projectile at a position off a vertical wall at a X position
+func (p Projectile) Ricochet(pos geometry.Point, wall float32) (geometry.Point, Projectile) {
+	pos.X = wall - (pos.X - wall)
+	p.Velocity.X = -p.Velocity.X * p.Bounce
+	return pos, p
+}
+
+// Predict the trajectory of a Projectile from a position for a time, in a number of steps
+func (p Projectile) Predict(from geometry.Point, constrain *Constrain, time float32, steps int, scale float32) []geometry.Point {
+	points := make([]geometry.Point, steps+1)
+	points[0] = from
+	delta := time / float32(steps)
+	for i := 1; i <= steps; i++ {
+		from, p = p.Step(from, constrain, delta, scale)
+		points[i] = from
+	}
+	return points
+}
+
+// bounce a value off a min and a max, without going over them, and return if we have bounced
+func bounce(value, min, max float32) (float32, bool) {
+	// without range there is nothing to bounce off
+	if max <= min {
+		return value, false
+	}
+	if value < min {
+		return float32(math.Min(float64(min+(min-value)), float64(max))), true
+	}
+	if value > max {
+		return float32(math.Max(float64(max-(value-max)), float64(min))), true
+	}
+	return value, false
+}
+
 // System Create the Movement system
 func System(engine *gosge.Engine, gs geometry.Scale) error {
 	ms := movementSystem{
-		gs: gs,
+		gs:     gs,
+		scroll: 1,
 	}
 
 	engine.World().AddSystem(ms.system)
+	engine.World().AddSystem(ms.projectileSystem)
+	engine.World().AddListener(ms.stateListener, ScrollSpeedEventType, HoldScrollEventType,
+		TurbulenceEventType)
+	ms.Track(engine.World())
 
 	return nil
 }
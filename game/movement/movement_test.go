@@ -0,0 +1,160 @@
+/*
+ * Copyright (c) 2020 Juan Medina.
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package movement
+
+import (
+	"fmt"
+	"github.com/juan-medina/gosge/components/geometry"
+	"testing"
+)
+
+func TestProjectile_Step(t *testing.T) {
+	type tc struct {
+		given     Projectile
+		from      geometry.Point
+		constrain *Constrain
+		expectPos geometry.Point
+		expectVel geometry.Point
+	}
+
+	box := &Constrain{
+		Min: geometry.Point{X: 0, Y: 0},
+		Max: geometry.Point{X: 100, Y: 100},
+	}
+
+	cases := []tc{
+		{
+			given:     Projectile{Velocity: geometry.Point{X: 10, Y: 0}},
+			from:      geometry.Point{X: 50, Y: 50},
+			expectPos: geometry.Point{X: 60, Y: 50},
+			expectVel: geometry.Point{X: 10, Y: 0},
+		},
+		{
+			given:     Projectile{Velocity: geometry.Point{X: 10, Y: 0}, Gravity: 20},
+			from:      geometry.Point{X: 50, Y: 50},
+			expectPos: geometry.Point{X: 60, Y: 70},
+			expectVel: geometry.Point{X: 10, Y: 20},
+		},
+		{
+			given:     Projectile{Velocity: geometry.Point{X: 10, Y: -60}, Bounce: 0.5},
+			from:      geometry.Point{X: 50, Y: 20},
+			constrain: box,
+			expectPos: geometry.Point{X: 60, Y: 40},
+			expectVel: geometry.Point{X: 10, Y: 30},
+		},
+		{
+			given:     Projectile{Velocity: geometry.Point{X: 10, Y: 60}, Bounce: 1},
+			from:      geometry.Point{X: 50, Y: 90},
+			constrain: box,
+			expectPos: geometry.Point{X: 60, Y: 50},
+			expectVel: geometry.Point{X: 10, Y: -60},
+		},
+		{
+			given: Projectile{Velocity: geometry.Point{X: 60, Y: 60}, Bounce: 1},
+			from:  geometry.Point{X: 50, Y: 90},
+			constrain: &Constrain{
+				Min: geometry.Point{X: 0, Y: 0},
+				Max: geometry.Point{X: 0, Y: 100},
+			},
+			expectPos: geometry.Point{X: 110, Y: 50},
+			expectVel: geometry.Point{X: 60, Y: -60},
+		},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("case %d", i+1), func(t *testing.T) {
+			pos, prj := c.given.Step(c.from, c.constrain, 1, 1)
+
+			if pos != c.expectPos {
+				t.Fatalf("step position error, got %v, expect %v", pos, c.expectPos)
+			}
+
+			if prj.Velocity != c.expectVel {
+				t.Fatalf("step velocity error, got %v, expect %v", prj.Velocity, c.expectVel)
+			}
+		})
+	}
+}
+
+func TestProjectile_Ricochet(t *testing.T) {
+	type tc struct {
+		given     Projectile
+		from      geometry.Point
+		wall      float32
+		expectPos geometry.Point
+		expectVel geometry.Point
+	}
+
+	cases := []tc{
+		{
+			given:     Projectile{Velocity: geometry.Point{X: 10, Y: 5}, Bounce: 1},
+			from:      geometry.Point{X: 60, Y: 50},
+			wall:      50,
+			expectPos: geometry.Point{X: 40, Y: 50},
+			expectVel: geometry.Point{X: -10, Y: 5},
+		},
+		{
+			given:     Projectile{Velocity: geometry.Point{X: 10, Y: 5}, Bounce: 0.5},
+			from:      geometry.Point{X: 55, Y: 50},
+			wall:      50,
+			expectPos: geometry.Point{X: 45, Y: 50},
+			expectVel: geometry.Point{X: -5, Y: 5},
+		},
+		{
+			given:     Projectile{Velocity: geometry.Point{X: -20, Y: 0}, Bounce: 0.5},
+			from:      geometry.Point{X: 40, Y: 50},
+			wall:      50,
+			expectPos: geometry.Point{X: 60, Y: 50},
+			expectVel: geometry.Point{X: 10, Y: 0},
+		},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("case %d", i+1), func(t *testing.T) {
+			pos, prj := c.given.Ricochet(c.from, c.wall)
+
+			if pos != c.expectPos {
+				t.Fatalf("ricochet position error, got %v, expect %v", pos, c.expectPos)
+			}
+
+			if prj.Velocity != c.expectVel {
+				t.Fatalf("ricochet velocity error, got %v, expect %v", prj.Velocity, c.expectVel)
+			}
+		})
+	}
+}
+
+func TestProjectile_Predict(t *testing.T) {
+	prj := Projectile{Velocity: geometry.Point{X: 10, Y: 5}, Life: 10}
+
+	points := prj.Predict(geometry.Point{}, nil, 2, 4, 2)
+
+	if got := len(points); got != 5 {
+		t.Fatalf("predict points error, got %v, expect %v", got, 5)
+	}
+
+	expect := geometry.Point{X: 40, Y: 20}
+	if got := points[4]; got != expect {
+		t.Fatalf("predict end error, got %v, expect %v", got, expect)
+	}
+}
@@ -0,0 +1,588 @@
+/*
+ * Copyright (c) 2020 Juan Medina.
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package pause
+
+import (
+	"fmt"
+	"github.com/juan-medina/goecs"
+	"github.com/juan-medina/gosge"
+	"github.com/juan-medina/gosge/components/animation"
+	"github.com/juan-medina/gosge/components/audio"
+	"github.com/juan-medina/gosge/components/color"
+	"github.com/juan-medina/gosge/components/effects"
+	"github.com/juan-medina/gosge/components/geometry"
+	"github.com/juan-medina/gosge/components/shapes"
+	"github.com/juan-medina/gosge/components/ui"
+	"github.com/juan-medina/gosge/events"
+	"github.com/juan-medina/mesh2prod/game/constants"
+	"reflect"
+)
+
+const (
+	font              = "resources/fonts/go_regular.fnt" // our message text font
+	fontBigSize       = 60                               // big text font size
+	fontSmallSize     = 30                               // small text font size
+	clickSound        = "resources/audio/click.wav"      // button click sound
+	shadowExtraWidth  = 3                                // the x offset for the buttons shadow
+	shadowExtraHeight = 3                                // the y offset for the buttons shadow
+	controlBorder     = 2                                // controls border thickness
+	pausePage         = "pause"                          // pause page
+	optionsPage       = "options"                        // options page
+	overlayDepth      = -200                             // depth of the overlay
+)
+
+// ToggleEvent is trigger to pause or resume the game, or go back if we are in a sub page
+type ToggleEvent struct{}
+
+// ToggleEventType is the reflect.Type of ToggleEvent
+var ToggleEventType = reflect.TypeOf(ToggleEvent{})
+
+// StateEvent is trigger when the game is paused or resumed
+type StateEvent struct {
+	Paused bool // Paused indicates if the game is paused
+}
+
+// StateEventType is the reflect.Type of StateEvent
+var StateEventType = reflect.TypeOf(StateEvent{})
+
+// State keep track of the pause, to be embedded in the systems that freeze while the game is paused
+type State struct {
+	paused bool // is the game paused
+}
+
+// Track the StateEvent to keep the State updated
+func (s *State) Track(world *goecs.World) {
+	world.AddListener(s.trackListener, StateEventType)
+}
+
+// IsPaused returns if the game is paused
+func (s State) IsPaused() bool {
+	return s.paused
+}
+
+func (s *State) trackListener(_ *goecs.World, signal interface{}, _ float32) error {
+	switch e := signal.(type) {
+	case StateEvent:
+		s.paused = e.Paused
+	}
+	return nil
+}
+
+type pauseSystem struct {
+	gs       geometry.Scale
+	dr       geometry.Size
+	eng      *gosge.Engine
+	paused   bool          // is the game paused
+	page     string        // current page
+	musics   []string      // musics that we have paused
+	volume   *goecs.Entity // master volume bar
+	volLabel *goecs.Entity // master volume label
+}
+
+// load the system
+func (ps *pauseSystem) load(eng *gosge.Engine) error {
+	var err error
+
+	// pre-load font
+	if err = eng.LoadFont(font); err != nil {
+		return err
+	}
+
+	// pre-load click sound
+	if err = eng.LoadSound(clickSound); err != nil {
+		return err
+	}
+
+	// get the world
+	world := eng.World()
+
+	// add the overlay
+	ps.addOverlay(world)
+
+	// add the pause page
+	ps.addPausePage(world)
+
+	// add the options page
+	ps.addOptionsPage(world)
+
+	// listen to toggle
+	world.AddListener(ps.toggleListener, ToggleEventType)
+
+	// listen to state changes
+	world.AddListener(ps.stateListener, StateEventType)
+
+	// listen to page changes
+	world.AddListener(ps.pageListener, changePageEventType)
+
+	// listen to volume changes
+	world.AddListener(ps.volumeListener, volumeChangeEventType)
+
+	return nil
+}
+
+// add a dark overlay over all the game
+func (ps *pauseSystem) addOverlay(world *goecs.World) {
+	world.AddEntity(
+		shapes.SolidBox{
+			Size:  ps.dr,
+			Scale: ps.gs.Max,
+		},
+		geometry.Point{},
+		color.Black.Alpha(120),
+		effects.Layer{Depth: overlayDepth},
+		page{},
+		effects.Hide{},
+	)
+}
+
+// add a panel with a title in the center of the screen
+func (ps *pauseSystem) addPanel(world *goecs.World, name, title string, size geometry.Size) geometry.Point {
+	pos := geometry.Point{
+		X: (ps.dr.Width * ps.gs.Point.X * 0.5) - (size.Width * ps.gs.Max * 0.5),
+		Y: (ps.dr.Height * ps.gs.Point.Y * 0.5) - (size.Height * ps.gs.Max * 0.5),
+	}
+
+	world.AddEntity(
+		shapes.SolidBox{
+			Size:  size,
+			Scale: ps.gs.Max,
+		},
+		pos,
+		color.Gradient{
+			From:      color.DarkBlue.Alpha(210),
+			To:        color.SkyBlue.Alpha(190),
+			Direction: color.GradientVertical,
+		},
+		effects.Layer{Depth: overlayDepth - 1},
+		page{name: name},
+		effects.Hide{},
+	)
+
+	world.AddEntity(
+		shapes.Box{
+			Size:      size,
+			Scale:     ps.gs.Max,
+			Thickness: int32(controlBorder * ps.gs.Max),
+		},
+		pos,
+		color.White,
+		effects.Layer{Depth: overlayDepth - 1},
+		page{name: name},
+		effects.Hide{},
+	)
+
+	world.AddEntity(
+		ui.Text{
+			String:     title,
+			Size:       fontBigSize * ps.gs.Max,
+			Font:       font,
+			VAlignment: ui.MiddleVAlignment,
+			HAlignment: ui.CenterHAlignment,
+		},
+		geometry.Point{
+			X: pos.X + (size.Width * 0.5 * ps.gs.Max),
+			Y: pos.Y + (40 * ps.gs.Max),
+		},
+		color.White,
+		effects.Layer{Depth: overlayDepth - 2},
+		page{name: name},
+		effects.Hide{},
+	)
+
+	return pos
+}
+
+// add a button to a page
+func (ps *pauseSystem) addButton(world *goecs.World, name, text string, pos geometry.Point, size geometry.Size,
+	event interface{}, focus bool) {
+	world.AddEntity(
+		ui.FlatButton{
+			Shadow: geometry.Size{Width: shadowExtraWidth * ps.gs.Max, Height: shadowExtraHeight * ps.gs.Max},
+			Event:  event,
+			Sound:  clickSound,
+			Volume: 1,
+		},
+		pos,
+		shapes.Box{
+			Size:      size,
+			Scale:     ps.gs.Max,
+			Thickness: int32(controlBorder * ps.gs.Max),
+		},
+		ui.Text{
+			String:     text,
+			Size:       fontSmallSize * ps.gs.Max,
+			Font:       font,
+			VAlignment: ui.MiddleVAlignment,
+			HAlignment: ui.CenterHAlignment,
+		},
+		ui.ButtonColor{
+			Gradient: color.Gradient{
+				From: color.Red,
+				To:   color.DarkPurple,
+			},
+			Border: color.White,
+			Text:   color.SkyBlue,
+		},
+		effects.Layer{Depth: overlayDepth - 2},
+		page{name: name, focus: focus},
+		effects.Hide{},
+	)
+}
+
+// add the pause page
+func (ps *pauseSystem) addPausePage(world *goecs.World) {
+	panelSize := geometry.Size{
+		Width:  400,
+		Height: 360,
+	}
+
+	panelPos := ps.addPanel(world, pausePage, "Paused", panelSize)
+
+	buttonSize := geometry.Size{
+		Width:  250,
+		Height: 45,
+	}
+
+	buttonPos := geometry.Point{
+		X: panelPos.X + ((panelSize.Width - buttonSize.Width) * 0.5 * ps.gs.Max),
+		Y: panelPos.Y + (90 * ps.gs.Max),
+	}
+
+	ps.addButton(world, pausePage, "Resume", buttonPos, buttonSize, ToggleEvent{}, true)
+	buttonPos.Y += (buttonSize.Height + 15) * ps.gs.Max
+
+	ps.addButton(world, pausePage, "Restart", buttonPos, buttonSize, events.DelaySignal{
+		Signal: events.ChangeGameStage{Stage: "game"},
+		Time:   0.25,
+	}, false)
+	buttonPos.Y += (buttonSize.Height + 15) * ps.gs.Max
+
+	ps.addButton(world, pausePage, "Options", buttonPos, buttonSize, changePageEvent{name: optionsPage}, false)
+	buttonPos.Y += (buttonSize.Height + 15) * ps.gs.Max
+
+	ps.addButton(world, pausePage, "Quit", buttonPos, buttonSize, events.DelaySignal{
+		Signal: events.ChangeGameStage{Stage: "menu"},
+		Time:   0.25,
+	}, false)
+}
+
+// add the options page
+func (ps *pauseSystem) addOptionsPage(world *goecs.World) {
+	panelSize := geometry.Size{
+		Width:  520,
+		Height: 210,
+	}
+
+	panelPos := ps.addPanel(world, optionsPage, "Options", panelSize)
+
+	labelPos := geometry.Point{
+		X: panelPos.X + (10 * ps.gs.Max),
+		Y: panelPos.Y + (90 * ps.gs.Max),
+	}
+
+	world.AddEntity(
+		ui.Text{
+			String:     "Master Volume",
+			Size:       fontSmallSize * ps.gs.Max,
+			Font:       font,
+			VAlignment: ui.TopVAlignment,
+			HAlignment: ui.LeftHAlignment,
+		},
+		labelPos,
+		color.White,
+		effects.Layer{Depth: overlayDepth - 2},
+		page{name: optionsPage},
+		effects.Hide{},
+	)
+
+	controlPos := geometry.Point{
+		X: labelPos.X + (200 * ps.gs.Max),
+		Y: labelPos.Y,
+	}
+
+	controlSize := geometry.Size{
+		Width:  300,
+		Height: 40,
+	}
+
+	current := ps.eng.GetSettings().GetFloat32(constants.MasterVolumeConfig, constants.DefaultMasterVolume) * 100
+
+	ps.volume = world.AddEntity(
+		ui.ProgressBar{
+			Min:     0,
+			Max:     100,
+			Current: current,
+			Shadow: geometry.Size{
+				Width:  2 * ps.gs.Max,
+				Height: 2 * ps.gs.Max,
+			},
+			Sound:  clickSound,
+			Volume: 1,
+			Event:  volumeChangeEvent{},
+		},
+		ui.ProgressBarColor{
+			Solid: color.SkyBlue,
+			Gradient: color.Gradient{
+				From:      color.SkyBlue,
+				To:        color.DarkBlue,
+				Direction: color.GradientHorizontal,
+			},
+			Empty:  color.Blue.Blend(color.White, 0.65),
+			Border: color.DarkBlue,
+		},
+		shapes.Box{
+			Size:      controlSize,
+			Scale:     ps.gs.Max,
+			Thickness: int32(controlBorder * ps.gs.Max),
+		},
+		controlPos,
+		effects.Layer{Depth: overlayDepth - 2},
+		page{name: optionsPage},
+		effects.Hide{},
+	)
+
+	ps.volLabel = world.AddEntity(
+		ui.Text{
+			String:     volumeText(current),
+			Size:       fontSmallSize * ps.gs.Max,
+			Font:       font,
+			VAlignment: ui.MiddleVAlignment,
+			HAlignment: ui.CenterHAlignment,
+		},
+		geometry.Point{
+			X: controlPos.X + (controlSize.Width * 0.5 * ps.gs.Max),
+			Y: controlPos.Y + (controlSize.Height * 0.5 * ps.gs.Max),
+		},
+		color.White,
+		effects.Layer{Depth: overlayDepth - 3},
+		page{name: optionsPage},
+		effects.Hide{},
+	)
+
+	buttonSize := geometry.Size{
+		Width:  100,
+		Height: 40,
+	}
+
+	buttonPos := geometry.Point{
+		X: controlPos.X,
+		Y: controlPos.Y + (60 * ps.gs.Max),
+	}
+
+	ps.addButton(world, optionsPage, "Back", buttonPos, buttonSize, changePageEvent{name: pausePage}, true)
+}
+
+// the text for a volume value
+func volumeText(value float32) string {
+	if int(value) == 0 {
+		return "Muted"
+	}
+	return fmt.Sprintf("%d%%", int(value))
+}
+
+// toggle the pause
+func (ps *pauseSystem) toggleListener(world *goecs.World, signal interface{}, _ float32) error {
+	switch signal.(type) {
+	case ToggleEvent:
+		// in a sub page we go back to the pause page
+		if ps.paused && ps.page != pausePage {
+			world.Signal(changePageEvent{name: pausePage})
+			return nil
+		}
+		world.Signal(StateEvent{Paused: !ps.paused})
+	}
+	return nil
+}
+
+// freeze or resume the game
+func (ps *pauseSystem) stateListener(world *goecs.World, signal interface{}, _ float32) error {
+	switch e := signal.(type) {
+	case StateEvent:
+		if e.Paused == ps.paused {
+			return nil
+		}
+		ps.paused = e.Paused
+		if ps.paused {
+			ps.freeze(world)
+			world.Signal(changePageEvent{name: pausePage})
+		} else {
+			ps.unFreeze(world)
+			world.Signal(changePageEvent{name: ""})
+		}
+	}
+	return nil
+}
+
+// freeze animations, blinks and music
+func (ps *pauseSystem) freeze(world *goecs.World) {
+	// we keep the animation in other component so it will not update
+	for it := world.Iterator(animation.TYPE.Animation); it != nil; it = it.Next() {
+		ent := it.Value()
+		frz := frozenOf(ent)
+		anim := animation.Get.Animation(ent)
+		frz.anim = &anim
+		ent.Set(frz)
+		ent.Remove(animation.TYPE.Animation)
+	}
+
+	// same for the blinks, but not for the controls that blink when focused
+	for it := world.Iterator(effects.TYPE.AlternateColor); it != nil; it = it.Next() {
+		ent := it.Value()
+		if ent.Contains(ui.TYPE.ControlState) {
+			continue
+		}
+		frz := frozenOf(ent)
+		blink := effects.Get.AlternateColor(ent)
+		frz.blink = &blink
+		ent.Set(frz)
+		ent.Remove(effects.TYPE.AlternateColor)
+	}
+
+	// pause any music that is playing
+	ps.musics = ps.musics[:0]
+	for it := world.Iterator(audio.TYPE.MusicState); it != nil; it = it.Next() {
+		sta := audio.Get.MusicState(it.Value())
+		if sta.PlayingState == audio.StatePlaying {
+			ps.musics = append(ps.musics, sta.Name)
+			world.Signal(events.PauseMusicEvent{Name: sta.Name})
+		}
+	}
+}
+
+// restore animations, blinks and music
+func (ps *pauseSystem) unFreeze(world *goecs.World) {
+	for it := world.Iterator(frozenType); it != nil; it = it.Next() {
+		ent := it.Value()
+		frz := ent.Get(frozenType).(frozen)
+		if frz.anim != nil {
+			ent.Add(*frz.anim)
+		}
+		if frz.blink != nil {
+			ent.Add(*frz.blink)
+		}
+		ent.Remove(frozenType)
+	}
+
+	for _, name := range ps.musics {
+		world.Signal(events.ResumeMusicEvent{Name: name})
+	}
+	ps.musics = ps.musics[:0]
+}
+
+// show a page, empty name to hide all
+func (ps *pauseSystem) pageListener(world *goecs.World, signal interface{}, _ float32) error {
+	switch e := signal.(type) {
+	case changePageEvent:
+		ps.page = e.name
+		for it := world.Iterator(pageType); it != nil; it = it.Next() {
+			ent := it.Value()
+			pg := ent.Get(pageType).(page)
+			// the overlay, with no name, is visible in any page
+			if e.name != "" && (pg.name == e.name || pg.name == "") {
+				if pg.focus {
+					world.Signal(events.FocusOnControlEvent{Control: ent})
+				}
+				if ent.Contains(effects.TYPE.Hide) {
+					ent.Remove(effects.TYPE.Hide)
+				}
+			} else {
+				if ent.NotContains(effects.TYPE.Hide) {
+					ent.Add(effects.Hide{})
+				}
+			}
+		}
+		if e.name == "" {
+			world.Signal(events.ClearFocusEvent{})
+		}
+	}
+	return nil
+}
+
+// update and save the master volume
+func (ps *pauseSystem) volumeListener(world *goecs.World, signal interface{}, _ float32) error {
+	switch signal.(type) {
+	case volumeChangeEvent:
+		bar := ui.Get.ProgressBar(ps.volume)
+		text := ui.Get.Text(ps.volLabel)
+		text.String = volumeText(bar.Current)
+		ps.volLabel.Set(text)
+		ps.eng.GetSettings().SetFloat32(constants.MasterVolumeConfig, bar.Current/100)
+		world.Signal(events.ChangeMasterVolumeEvent{Volume: bar.Current / 100})
+	}
+	return nil
+}
+
+// System create the pause system, it should be added after any other system that listen to StateEvent
+func System(engine *gosge.Engine, gs geometry.Scale, dr geometry.Size) error {
+	ps := pauseSystem{
+		gs:  gs,
+		dr:  dr,
+		eng: engine,
+	}
+	return ps.load(engine)
+}
+
+// frozen keep an animation, and a blink, while we are paused
+type frozen struct {
+	anim  *animation.Animation
+	blink *effects.AlternateColor
+}
+
+var frozenType = reflect.TypeOf(frozen{})
+
+// get what we have frozen for an entity, if anything
+func frozenOf(ent *goecs.Entity) frozen {
+	if ent.Contains(frozenType) {
+		return ent.Get(frozenType).(frozen)
+	}
+	return frozen{}
+}
+
+// RemoveBlink removes the blink of an entity, even if it is frozen while paused
+func RemoveBlink(ent *goecs.Entity) {
+	ent.Remove(effects.TYPE.AlternateColor)
+	ent.Remove(effects.TYPE.AlternateColorState)
+	if ent.Contains(frozenType) {
+		frz := ent.Get(frozenType).(frozen)
+		frz.blink = nil
+		ent.Set(frz)
+	}
+}
+
+// page is a component for the entities in a page
+type page struct {
+	name  string
+	focus bool
+}
+
+var pageType = reflect.TypeOf(page{})
+
+type changePageEvent struct {
+	name string
+}
+
+var changePageEventType = reflect.TypeOf(changePageEvent{})
+
+type volumeChangeEvent struct{}
+
+var volumeChangeEventType = reflect.TypeOf(volumeChangeEvent{})
@@ -27,37 +27,46 @@ import (
 	"github.com/juan-medina/goecs"
 	"github.com/juan-medina/gosge"
 	"github.com/juan-medina/gosge/components/animation"
-	"github.com/juan-medina/gosge/components/device"
 	"github.com/juan-medina/gosge/components/effects"
 	"github.com/juan-medina/gosge/components/geometry"
-	"github.com/juan-medina/gosge/events"
 	"github.com/juan-medina/mesh2prod/game/component"
-	"github.com/juan-medina/mesh2prod/game/constants"
+	"github.com/juan-medina/mesh2prod/game/input"
 	"github.com/juan-medina/mesh2prod/game/movement"
+	"github.com/juan-medina/mesh2prod/game/pause"
+	"github.com/juan-medina/mesh2prod/game/skin"
+	"github.com/juan-medina/mesh2prod/game/upgrade"
 	"github.com/juan-medina/mesh2prod/game/winning"
 	"reflect"
 )
 
 const (
-	gopherPlaneAnim = "gopher_plane_%d.png" // base animation for our gopher
-	planeScale      = float32(0.5)          // plane scale
-	planeX          = 400                   // plane X position
-	planeSpeed      = float32(320)          // plane speed
-	animSpeedSlow   = 0.65                  // animation slow speed
-	animSpeedFast   = 1                     // animation fast speed
-	joinShiftX      = 20                    // shift in X for the joint
-	joinShiftY      = 5                     // shift in Y for the joint
-	gunShiftX       = 70                    // shift in x for the gun
-	gunShiftY       = 50                    // shift in in for the gun
+	planeScale     = float32(0.5) // plane scale
+	planeX         = 400          // plane X position
+	planeSpeed     = float32(320) // plane speed
+	planeSpeedX    = float32(260) // plane horizontal speed
+	bandBack       = 250          // how far back from planeX the plane could move
+	bandFront      = 450          // how far forward from planeX the plane could move
+	boostFactor    = float32(2)   // scroll speed factor when boosting
+	brakeFactor    = float32(0.5) // scroll speed factor when braking
+	pointerEase    = float32(6)   // how fast the plane eases toward the pointer
+	pointerMinDiff = 2            // min difference with the pointer to move
+	animSpeedSlow  = 0.65         // animation slow speed
+	animSpeedFast  = 1            // animation fast speed
 )
 
 type planeSystem struct {
+	pause.State
 	gs      geometry.Scale
 	dr      geometry.Size
 	plane   *goecs.Entity
 	lastPos geometry.Point
 	size    geometry.Size
 	end     bool
+	scroll  float32        // current scroll speed factor
+	pointer bool           // are we steering with the pointer
+	targetY float32        // Y position of the pointer
+	speed   geometry.Point // plane speed, with the upgrade applied
+	skin    skin.Plane     // plane skin
 }
 
 // add the background
@@ -67,31 +76,41 @@ func (ps *planeSystem) load(eng *gosge.Engine) error {
 	// get the ECS world
 	world := eng.World()
 
+	// apply the plane speed upgrade
+	factor := upgrade.PlaneSpeed.Value(eng.GetSettings())
+	ps.speed = geometry.Point{
+		X: planeSpeedX * factor,
+		Y: planeSpeed * factor,
+	}
+
 	// get the size of the first sprite for our plane
-	if ps.size, err = eng.GetSpriteSize(constants.SpriteSheet, fmt.Sprintf(gopherPlaneAnim, 1)); err != nil {
+	if ps.size, err = eng.GetSpriteSize(ps.skin.Sheet, fmt.Sprintf(ps.skin.Base, 1)); err != nil {
 		return err
 	}
 
 	// calculate halve of the height
 	halveHeight := (ps.size.Height / 2) * planeScale
 
+	// our starting X
+	startX := (ps.size.Width / 2 * planeScale * ps.gs.Max) + planeX*ps.gs.Max
+
 	// add our plane
 	ps.plane = world.AddEntity(
 		animation.Animation{
 			Sequences: map[string]animation.Sequence{
 				"flying": {
-					Sheet:  constants.SpriteSheet,
-					Base:   gopherPlaneAnim,
+					Sheet:  ps.skin.Sheet,
+					Base:   ps.skin.Base,
 					Scale:  ps.gs.Max * planeScale,
-					Frames: 2,
-					Delay:  0.065,
+					Frames: ps.skin.Frames,
+					Delay:  ps.skin.Delay,
 				},
 			},
 			Current: "flying",
 			Speed:   animSpeedSlow,
 		},
 		geometry.Point{
-			X: (ps.size.Width / 2 * planeScale * ps.gs.Max) + planeX*ps.gs.Max,
+			X: startX,
 			Y: ps.dr.Height / 2 * ps.gs.Max,
 		},
 		movement.Movement{
@@ -99,49 +118,63 @@ func (ps *planeSystem) load(eng *gosge.Engine) error {
 		},
 		movement.Constrain{
 			Min: geometry.Point{
-				X: 0,
+				X: startX - (bandBack * ps.gs.Max),
 				Y: halveHeight * ps.gs.Max,
 			},
 			Max: geometry.Point{
-				X: ps.dr.Width * ps.gs.Max,
+				X: startX + (bandFront * ps.gs.Max),
 				Y: (ps.dr.Height - halveHeight) * ps.gs.Max,
 			},
 		},
+		ps.skin.Color(),
 		effects.Layer{Depth: 0},
+		movement.Drift{},
 		component.Plane{},
 	)
 
-	// add the keys listener
-	world.AddListener(ps.keyMoveListener, events.TYPE.KeyUpEvent, events.TYPE.KeyDownEvent)
-
-	// add the gamepad listener
-	world.AddListener(ps.gamePadMoveListener, events.TYPE.GamePadButtonUpEvent, events.TYPE.GamePadButtonDownEvent)
+	// add the actions listener
+	world.AddListener(ps.actionListener, input.ActionEventType)
 
 	// add the stick listener
-	world.AddListener(ps.gamepadStickListener, events.TYPE.GamePadStickMoveEvent)
+	world.AddListener(ps.axisListener, input.AxisEventType)
+
+	// steer with the pointer if is our scheme
+	if input.Load(eng.GetSettings()).Scheme == input.Pointer {
+		ps.pointer = true
+		ps.targetY = geometry.Get.Point(ps.plane).Y
+
+		// add the pointer listener
+		world.AddListener(ps.pointerListener, input.PointerEventType)
+
+		// add the pointer steering system
+		world.AddSystem(ps.pointerSystem)
+	}
 
 	// add system to notify the world of position changes
 	world.AddSystem(ps.notifyPositionChanges)
 
+	// keep track of the pause
+	ps.Track(world)
+
 	// listen to level events
-	world.AddListener(ps.levelEvents, winning.LevelEndEventType)
+	world.AddListener(ps.levelEvents, winning.LevelEndEventType, pause.StateEventType)
 
 	return nil
 }
 
-func (ps *planeSystem) changePlaneSpeed(speed float32) {
+func (ps *planeSystem) changePlaneSpeed(speed geometry.Point) {
 	// get the Movement and animation components
 	mov := ps.plane.Get(movement.Type).(movement.Movement)
 	anim := animation.Get.Animation(ps.plane)
 
 	// set the speed
-	mov.Amount.Y = speed
+	mov.Amount = speed
 
 	// animate faster
 	anim.Speed = animSpeedFast
 
 	// if we stop
-	if speed == 0 {
+	if speed.X == 0 && speed.Y == 0 {
 		// animated slower
 		anim.Speed = animSpeedSlow
 	}
@@ -151,80 +184,111 @@ func (ps *planeSystem) changePlaneSpeed(speed float32) {
 	ps.plane.Set(anim)
 }
 
-func (ps *planeSystem) keyMoveListener(_ *goecs.World, signal interface{}, _ float32) error {
-	if ps.end {
+// change the scroll speed factor, if is different
+func (ps *planeSystem) changeScroll(world *goecs.World, factor float32) {
+	if ps.scroll != factor {
+		ps.scroll = factor
+		world.Signal(movement.ScrollSpeedEvent{Factor: factor})
+	}
+}
+
+func (ps *planeSystem) actionListener(world *goecs.World, signal interface{}, _ float32) error {
+	if ps.end || ps.IsPaused() {
 		return nil
 	}
 	switch e := signal.(type) {
-	// if we got a key event
-	case events.KeyDownEvent:
-		// if we have use the cursor up or down
-		if e.Key == device.KeyUp || e.Key == device.KeyDown {
-			speed := planeSpeed
-			if e.Key == device.KeyUp {
-				speed = -speed
+	// if we got an action
+	case input.ActionEvent:
+		speed := ps.plane.Get(movement.Type).(movement.Movement).Amount
+		switch e.Action {
+		// if we have move up or down, unless we use the pointer
+		case input.MoveUp, input.MoveDown:
+			if ps.pointer {
+				return nil
+			}
+			speed.Y = 0
+			if e.Pressed {
+				speed.Y = ps.speed.Y
+				if e.Action == input.MoveUp {
+					speed.Y = -speed.Y
+				}
 			}
 			ps.changePlaneSpeed(speed)
-		}
-	case events.KeyUpEvent:
-		// if we have use the cursor up or down
-		if e.Key == device.KeyUp || e.Key == device.KeyDown {
-			ps.changePlaneSpeed(0)
+		// if we have move left or right
+		case input.MoveLeft, input.MoveRight:
+			speed.X = 0
+			if e.Pressed {
+				speed.X = ps.speed.X
+				if e.Action == input.MoveLeft {
+					speed.X = -speed.X
+				}
+			}
+			ps.changePlaneSpeed(speed)
+		// if we boost or brake
+		case input.Boost, input.Brake:
+			factor := float32(1)
+			if e.Pressed {
+				factor = boostFactor
+				if e.Action == input.Brake {
+					factor = brakeFactor
+				}
+			}
+			ps.changeScroll(world, factor)
 		}
 	}
 	return nil
 }
 
-func (ps *planeSystem) gamepadStickListener(_ *goecs.World, signal interface{}, _ float32) error {
-	if ps.end {
+func (ps *planeSystem) axisListener(_ *goecs.World, signal interface{}, _ float32) error {
+	if ps.end || ps.IsPaused() {
 		return nil
 	}
 	switch v := signal.(type) {
-	case events.GamePadStickMoveEvent:
-		ps.changePlaneSpeed(planeSpeed * v.Movement.Y)
+	case input.AxisEvent:
+		speed := geometry.Point{
+			X: ps.speed.X * v.Movement.X,
+			Y: ps.speed.Y * v.Movement.Y,
+		}
+		if ps.pointer {
+			speed.Y = ps.plane.Get(movement.Type).(movement.Movement).Amount.Y
+		}
+		ps.changePlaneSpeed(speed)
 	}
 	return nil
 }
 
-func (ps *planeSystem) gamePadMoveListener(world *goecs.World, signal interface{}, delta float32) error {
-	var fs interface{} = nil
-	switch v := signal.(type) {
-	case events.GamePadButtonUpEvent:
-		fsu := events.KeyUpEvent{Key: device.FirstKey}
-		switch v.Button {
-		case device.GamepadUp:
-			fsu.Key = device.KeyUp
-		case device.GamepadDown:
-			fsu.Key = device.KeyDown
-		case device.GamepadLeft:
-			fsu.Key = device.KeyLeft
-		case device.GamepadRight:
-			fsu.Key = device.KeyRight
-		}
-		if fsu.Key != device.FirstKey {
-			fs = fsu
-		}
-	case events.GamePadButtonDownEvent:
-		fsd := events.KeyDownEvent{Key: device.FirstKey}
-		switch v.Button {
-		case device.GamepadUp:
-			fsd.Key = device.KeyUp
-		case device.GamepadDown:
-			fsd.Key = device.KeyDown
-		case device.GamepadLeft:
-			fsd.Key = device.KeyLeft
-		case device.GamepadRight:
-			fsd.Key = device.KeyRight
-		}
-		if fsd.Key != device.FirstKey {
-			fs = fsd
-		}
+func (ps *planeSystem) pointerListener(_ *goecs.World, signal interface{}, _ float32) error {
+	switch e := signal.(type) {
+	case input.PointerEvent:
+		ps.targetY = e.Pos.Y
+	}
+	return nil
+}
+
+// ease the plane toward the pointer, the movement constrains will keep it in the screen
+func (ps *planeSystem) pointerSystem(_ *goecs.World, _ float32) error {
+	if ps.end || ps.IsPaused() {
+		return nil
 	}
 
-	if fs != nil {
-		return ps.keyMoveListener(world, fs, delta)
+	pos := geometry.Get.Point(ps.plane)
+	speed := ps.plane.Get(movement.Type).(movement.Movement).Amount
+
+	diff := ps.targetY - pos.Y
+	if diff > -pointerMinDiff && diff < pointerMinDiff {
+		speed.Y = 0
+	} else {
+		// movement is scaled, so the difference need to be unscaled
+		speed.Y = (diff / ps.gs.Max) * pointerEase
+		if speed.Y > ps.speed.Y {
+			speed.Y = ps.speed.Y
+		} else if speed.Y < -ps.speed.Y {
+			speed.Y = -ps.speed.Y
+		}
 	}
 
+	ps.changePlaneSpeed(speed)
+
 	return nil
 }
 
@@ -235,13 +299,13 @@ func (ps *planeSystem) notifyPositionChanges(world *goecs.World, _ float32) erro
 		ps.lastPos = current
 
 		joint := geometry.Point{
-			X: current.X - (((ps.size.Width / 2) - joinShiftX) * planeScale * ps.gs.Max),
-			Y: current.Y - (joinShiftY * planeScale * ps.gs.Max),
+			X: current.X - (((ps.size.Width / 2) - ps.skin.Joint.X) * planeScale * ps.gs.Max),
+			Y: current.Y - (ps.skin.Joint.Y * planeScale * ps.gs.Max),
 		}
 
 		gun := geometry.Point{
-			X: current.X + (gunShiftX * planeScale * ps.gs.Max),
-			Y: current.Y + (gunShiftY * planeScale * ps.gs.Max),
+			X: current.X + (ps.skin.Gun.X * planeScale * ps.gs.Max),
+			Y: current.Y + (ps.skin.Gun.Y * planeScale * ps.gs.Max),
 		}
 
 		world.Signal(PositionChangeEvent{Pos: current, Joint: joint, Gun: gun})
@@ -250,21 +314,30 @@ func (ps *planeSystem) notifyPositionChanges(world *goecs.World, _ float32) erro
 	return nil
 }
 
-func (ps *planeSystem) levelEvents(_ *goecs.World, signal interface{}, _ float32) error {
-	switch signal.(type) {
+func (ps *planeSystem) levelEvents(world *goecs.World, signal interface{}, _ float32) error {
+	switch e := signal.(type) {
 	case winning.LevelEndEvent:
 		ps.end = true
+		ps.changeScroll(world, 1)
+	case pause.StateEvent:
+		// stop, so we do not keep moving if a key is released while paused
+		if e.Paused && !ps.end {
+			ps.changePlaneSpeed(geometry.Point{})
+			ps.changeScroll(world, 1)
+		}
 	}
 
 	return nil
 }
 
 // System create a plane system
-func System(engine *gosge.Engine, gs geometry.Scale, dr geometry.Size) error {
+func System(engine *gosge.Engine, gs geometry.Scale, dr geometry.Size, sk skin.Plane) error {
 	ps := planeSystem{
-		gs:    gs,
-		dr:    dr,
-		plane: nil,
+		gs:     gs,
+		dr:     dr,
+		plane:  nil,
+		scroll: 1,
+		skin:   sk,
 	}
 
 	return ps.load(engine)
@@ -0,0 +1,417 @@
+/*
+ * Copyright (c) 2020 Juan Medina.
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package powerup
+
+import (
+	"fmt"
+	"github.com/juan-medina/goecs"
+	"github.com/juan-medina/gosge"
+	"github.com/juan-medina/gosge/components/color"
+	"github.com/juan-medina/gosge/components/effects"
+	"github.com/juan-medina/gosge/components/geometry"
+	"github.com/juan-medina/gosge/components/sprite"
+	"github.com/juan-medina/gosge/components/ui"
+	"github.com/juan-medina/gosge/events"
+	"github.com/juan-medina/mesh2prod/game/collision"
+	"github.com/juan-medina/mesh2prod/game/component"
+	"github.com/juan-medina/mesh2prod/game/constants"
+	"github.com/juan-medina/mesh2prod/game/movement"
+	"github.com/juan-medina/mesh2prod/game/pause"
+	"github.com/juan-medina/mesh2prod/game/upgrade"
+	"github.com/juan-medina/mesh2prod/game/winning"
+	"math"
+	"math/rand"
+	"reflect"
+)
+
+// logic constants
+const (
+	pickupSprite = "box.png"                        // pickup sprite
+	pickupScale  = 0.35                             // pickup scale
+	pickupSpeed  = 25                               // pickup scroll speed (match block scroll)
+	spawnChance  = 0.3                              // chance to spawn a pickup when we clear enough blocks
+	minCleared   = 3                                // min blocks cleared at once to spawn a pickup
+	magnetSpeed  = 300                              // speed that the magnet pull pickups
+	repairBlocks = 5                                // mesh hits repaired by a mesh repair
+	font         = "resources/fonts/go_regular.fnt" // our text font
+	fontSize     = 30                               // text font size
+	pickupSound  = "resources/audio/win.wav"        // pickup sound
+)
+
+// Kind is a kind of power-up
+type Kind int
+
+// power-ups
+const (
+	RapidFire  = Kind(iota) // RapidFire fires twice as fast
+	SpreadShot              // SpreadShot fires three bullets
+	Shield                  // Shield the plane pass through blocks
+	Magnet                  // Magnet pull the pickups to the plane
+	MeshRepair              // MeshRepair repair some mesh hits
+	totalKinds
+)
+
+// info for a Kind of power-up
+type info struct {
+	name     string      // name to display
+	letter   string      // letter in the pickup
+	color    color.Solid // pickup color
+	duration float32     // duration in seconds, 0 if is instant
+}
+
+var (
+	kinds = map[Kind]info{
+		RapidFire:  {name: "Rapid Fire", letter: "R", color: color.Red, duration: 10},
+		SpreadShot: {name: "Spread Shot", letter: "S", color: color.Orange, duration: 10},
+		Shield:     {name: "Shield", letter: "D", color: color.SkyBlue, duration: 8},
+		Magnet:     {name: "Magnet", letter: "M", color: color.Purple, duration: 12},
+		MeshRepair: {name: "Mesh Repair", letter: "+", color: color.Green, duration: 0},
+	}
+)
+
+// SpawnEvent is a signal that an area has been clear, a pickup may spawn on its center
+type SpawnEvent struct {
+	At      geometry.Point // At is the center of the area
+	Cleared int            // Cleared is how many blocks has been clear
+}
+
+// SpawnEventType is the reflect.Type of SpawnEvent
+var SpawnEventType = reflect.TypeOf(SpawnEvent{})
+
+// DropEvent is a signal that a pickup should spawn, of a random kind
+type DropEvent struct {
+	At geometry.Point // At is where the pickup spawn
+}
+
+// DropEventType is the reflect.Type of DropEvent
+var DropEventType = reflect.TypeOf(DropEvent{})
+
+// StateEvent is trigger when a power-up is activated or expires
+type StateEvent struct {
+	Kind   Kind // Kind of power-up
+	Active bool // Active indicates if the power-up is active
+}
+
+// StateEventType is the reflect.Type of StateEvent
+var StateEventType = reflect.TypeOf(StateEvent{})
+
+type powerUpSystem struct {
+	pause.State
+	gs         geometry.Scale
+	dr         geometry.Size
+	eng        *gosge.Engine
+	pickupSize geometry.Size          // pickup sprite size
+	active     map[Kind]float32       // remaining time for the active power-ups
+	hud        map[Kind]*goecs.Entity // hud text for the active power-ups
+	end        bool
+}
+
+// load the system
+func (ps *powerUpSystem) load(eng *gosge.Engine) error {
+	var err error
+
+	// pre-load font
+	if err = eng.LoadFont(font); err != nil {
+		return err
+	}
+
+	// pre-load pickup sound
+	if err = eng.LoadSound(pickupSound); err != nil {
+		return err
+	}
+
+	// get the pickup size
+	if ps.pickupSize, err = eng.GetSpriteSize(constants.SpriteSheet, pickupSprite); err != nil {
+		return err
+	}
+
+	world := eng.World()
+
+	// listen to spawns
+	world.AddListener(ps.spawnListener, SpawnEventType, DropEventType)
+
+	// listen to pickups
+	world.AddListener(ps.pickupListener, collision.PlanePickupEventType)
+
+	// power-ups timers
+	world.AddSystem(ps.timerSystem)
+
+	// pickups movement
+	world.AddSystem(ps.pickupSystem)
+
+	// keep track of the pause
+	ps.Track(world)
+
+	// listen to level events
+	world.AddListener(ps.levelEvents, winning.LevelEndEventType)
+
+	// start with the power-ups from the upgrade
+	ps.startPowerUps(world, int(upgrade.StartPowerUps.Value(eng.GetSettings())))
+
+	return nil
+}
+
+// activate random timed power-ups at the level start, without repeating them
+func (ps *powerUpSystem) startPowerUps(world *goecs.World, count int) {
+	if count <= 0 {
+		return
+	}
+	timed := make([]Kind, 0)
+	for k := Kind(0); k < totalKinds; k++ {
+		if kinds[k].duration > 0 {
+			timed = append(timed, k)
+		}
+	}
+	rand.Shuffle(len(timed), func(i, j int) { timed[i], timed[j] = timed[j], timed[i] })
+	for i := 0; i < count && i < len(timed); i++ {
+		ps.activate(world, timed[i])
+		ps.active[timed[i]] = kinds[timed[i]].duration
+	}
+	ps.updateHud()
+}
+
+// maybe spawn a pickup
+func (ps *powerUpSystem) spawnListener(world *goecs.World, signal interface{}, _ float32) error {
+	if ps.end {
+		return nil
+	}
+	switch e := signal.(type) {
+	case SpawnEvent:
+		if e.Cleared >= minCleared && rand.Float32() < spawnChance {
+			ps.spawn(world, Kind(rand.Intn(int(totalKinds))), e.At)
+		}
+	case DropEvent:
+		ps.spawn(world, Kind(rand.Intn(int(totalKinds))), e.At)
+	}
+	return nil
+}
+
+// spawn a pickup
+func (ps *powerUpSystem) spawn(world *goecs.World, kind Kind, at geometry.Point) {
+	mov := movement.Movement{
+		Amount: geometry.Point{
+			X: -pickupSpeed * ps.gs.Max,
+		},
+	}
+
+	text := world.AddEntity(
+		ui.Text{
+			String:     kinds[kind].letter,
+			Size:       fontSize * ps.gs.Max,
+			Font:       font,
+			VAlignment: ui.MiddleVAlignment,
+			HAlignment: ui.CenterHAlignment,
+		},
+		at,
+		color.White,
+		mov,
+		movement.Scroll{},
+		effects.Layer{Depth: -1},
+	)
+
+	world.AddEntity(
+		sprite.Sprite{
+			Sheet: constants.SpriteSheet,
+			Name:  pickupSprite,
+			Scale: ps.gs.Max * pickupScale,
+		},
+		at,
+		kinds[kind].color,
+		mov,
+		movement.Scroll{},
+		component.Pickup{Kind: int(kind), Text: text},
+		effects.Layer{Depth: 0},
+	)
+}
+
+// activate a power-up
+func (ps *powerUpSystem) pickupListener(world *goecs.World, signal interface{}, _ float32) error {
+	if ps.end {
+		return nil
+	}
+	switch e := signal.(type) {
+	case collision.PlanePickupEvent:
+		kind := Kind(e.Pickup.Kind)
+		world.Signal(events.PlaySoundEvent{Name: pickupSound, Volume: 0.5})
+		if kinds[kind].duration == 0 {
+			ps.instant(world, kind)
+			return nil
+		}
+		if _, ok := ps.active[kind]; !ok {
+			ps.activate(world, kind)
+		}
+		ps.active[kind] = kinds[kind].duration
+		ps.updateHud()
+	}
+	return nil
+}
+
+// apply a instant power-up
+func (ps *powerUpSystem) instant(world *goecs.World, kind Kind) {
+	switch kind {
+	case MeshRepair:
+		world.Signal(winning.RepairMeshEvent{Blocks: repairBlocks})
+	}
+}
+
+// activate a timed power-up
+func (ps *powerUpSystem) activate(world *goecs.World, kind Kind) {
+	if kind == Shield {
+		world.Iterator(component.TYPE.Plane).Value().Set(component.Shield{})
+	}
+
+	ps.hud[kind] = world.AddEntity(
+		ui.Text{
+			Size:       fontSize * ps.gs.Max,
+			Font:       font,
+			VAlignment: ui.BottomVAlignment,
+			HAlignment: ui.LeftHAlignment,
+		},
+		geometry.Point{},
+		kinds[kind].color,
+		effects.Layer{Depth: -100},
+	)
+
+	world.Signal(StateEvent{Kind: kind, Active: true})
+}
+
+// deactivate a timed power-up
+func (ps *powerUpSystem) deactivate(world *goecs.World, kind Kind) {
+	if kind == Shield {
+		world.Iterator(component.TYPE.Plane).Value().Remove(component.TYPE.Shield)
+	}
+
+	delete(ps.active, kind)
+	_ = world.Remove(ps.hud[kind])
+	delete(ps.hud, kind)
+
+	world.Signal(StateEvent{Kind: kind, Active: false})
+}
+
+// update the hud, the active power-ups are stacked at the bottom left
+func (ps *powerUpSystem) updateHud() {
+	pos := geometry.Point{
+		X: 10 * ps.gs.Max,
+		Y: (ps.dr.Height - 10) * ps.gs.Max,
+	}
+	for k := Kind(0); k < totalKinds; k++ {
+		if ent, ok := ps.hud[k]; ok {
+			text := ui.Get.Text(ent)
+			text.String = fmt.Sprintf("%s %ds", kinds[k].name, int(math.Ceil(float64(ps.active[k]))))
+			ent.Set(text)
+			ent.Set(pos)
+			pos.Y -= (fontSize + 5) * ps.gs.Max
+		}
+	}
+}
+
+// count down the active power-ups
+func (ps *powerUpSystem) timerSystem(world *goecs.World, delta float32) error {
+	if ps.end || ps.IsPaused() || len(ps.active) == 0 {
+		return nil
+	}
+	for k := Kind(0); k < totalKinds; k++ {
+		if remain, ok := ps.active[k]; ok {
+			if remain -= delta; remain <= 0 {
+				ps.deactivate(world, k)
+			} else {
+				ps.active[k] = remain
+			}
+		}
+	}
+	ps.updateHud()
+	return nil
+}
+
+// remove pickups that are off screen, and pull them with the magnet
+func (ps *powerUpSystem) pickupSystem(world *goecs.World, _ float32) error {
+	if ps.IsPaused() {
+		return nil
+	}
+
+	_, magnet := ps.active[Magnet]
+
+	var planePos geometry.Point
+	if magnet {
+		planePos = geometry.Get.Point(world.Iterator(component.TYPE.Plane).Value())
+	}
+
+	minX := -ps.pickupSize.Width * pickupScale * ps.gs.Max
+
+	for it := world.Iterator(component.TYPE.Pickup, geometry.TYPE.Point); it != nil; it = it.Next() {
+		ent := it.Value()
+		pos := geometry.Get.Point(ent)
+		pickup := component.Get.Pickup(ent)
+
+		if pos.X < minX {
+			_ = world.Remove(pickup.Text)
+			_ = world.Remove(ent)
+			continue
+		}
+
+		if magnet {
+			diff := geometry.Point{X: planePos.X - pos.X, Y: planePos.Y - pos.Y}
+			length := float32(math.Sqrt(float64(diff.X*diff.X + diff.Y*diff.Y)))
+			if length > 0 {
+				mov := movement.Movement{
+					Amount: geometry.Point{
+						X: diff.X / length * magnetSpeed * ps.gs.Max,
+						Y: diff.Y / length * magnetSpeed * ps.gs.Max,
+					},
+				}
+				// pulled pickups does not scroll
+				ent.Set(mov)
+				ent.Remove(movement.ScrollType)
+				pickup.Text.Set(mov)
+				pickup.Text.Remove(movement.ScrollType)
+				pickup.Text.Set(pos)
+			}
+		}
+	}
+	return nil
+}
+
+func (ps *powerUpSystem) levelEvents(world *goecs.World, signal interface{}, _ float32) error {
+	switch signal.(type) {
+	case winning.LevelEndEvent:
+		for k := Kind(0); k < totalKinds; k++ {
+			if _, ok := ps.active[k]; ok {
+				ps.deactivate(world, k)
+			}
+		}
+		ps.end = true
+	}
+	return nil
+}
+
+// System create the power-up system
+func System(engine *gosge.Engine, gs geometry.Scale, dr geometry.Size) error {
+	ps := powerUpSystem{
+		gs:     gs,
+		dr:     dr,
+		eng:    engine,
+		active: make(map[Kind]float32),
+		hud:    make(map[Kind]*goecs.Entity),
+	}
+	return ps.load(engine)
+}
@@ -0,0 +1,155 @@
+/*
+ * Copyright (c) 2020 Juan Medina.
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package rope
+
+import (
+	"github.com/juan-medina/gosge/components/geometry"
+	"math"
+)
+
+// Rope is a verlet rope, with its start and end pinned
+type Rope struct {
+	Points     []geometry.Point // Points of the rope, from the start to the end
+	Length     float32          // Length of the rope at rest
+	Stiffness  float32          // Stiffness of the rope, from 0 to 1
+	Gravity    float32          // Gravity that make the rope sag
+	Iterations int              // Iterations to solve the rope length on each update
+	prev       []geometry.Point // previous position of each point
+	snagged    []bool           // is each point snagged
+}
+
+// New creates a Rope in a straight line, with a number of segments
+func New(from, to geometry.Point, segments int, length, stiffness, gravity float32) *Rope {
+	r := &Rope{
+		Points:     make([]geometry.Point, segments+1),
+		Length:     length,
+		Stiffness:  stiffness,
+		Gravity:    gravity,
+		Iterations: 4,
+		prev:       make([]geometry.Point, segments+1),
+		snagged:    make([]bool, segments+1),
+	}
+	for i := range r.Points {
+		t := float32(i) / float32(segments)
+		r.Points[i] = geometry.Point{
+			X: from.X + (to.X-from.X)*t,
+			Y: from.Y + (to.Y-from.Y)*t,
+		}
+		r.prev[i] = r.Points[i]
+	}
+	return r
+}
+
+// Update simulate the Rope for a delta time with its ends at the given positions, the points that
+// are blocked get snagged until they are free, returns how many points are snagged
+func (r *Rope) Update(from, to geometry.Point, delta float32, blocked func(geometry.Point) bool) int {
+	last := len(r.Points) - 1
+	r.Points[0] = from
+	r.Points[last] = to
+
+	// move the points with their velocity and the gravity
+	snags := 0
+	for i := 1; i < last; i++ {
+		p := r.Points[i]
+		next := geometry.Point{
+			X: p.X + (p.X - r.prev[i].X),
+			Y: p.Y + (p.Y - r.prev[i].Y) + r.Gravity*delta*delta,
+		}
+		r.prev[i] = p
+		// a blocked point stay where it was
+		if r.snagged[i] = blocked != nil && blocked(next); r.snagged[i] {
+			snags++
+			continue
+		}
+		r.Points[i] = next
+	}
+
+	// keep the segments length
+	segment := r.Length / float32(last)
+	for it := 0; it < r.Iterations; it++ {
+		for i := 0; i < last; i++ {
+			r.solve(i, i+1, segment, i == 0 || r.snagged[i], i+1 == last || r.snagged[i+1])
+		}
+	}
+
+	return snags
+}
+
+// solve the distance between two points, pinned points do not move
+func (r *Rope) solve(a, b int, segment float32, pinnedA, pinnedB bool) {
+	if pinnedA && pinnedB {
+		return
+	}
+	pa := r.Points[a]
+	pb := r.Points[b]
+	dx := pb.X - pa.X
+	dy := pb.Y - pa.Y
+	dist := float32(math.Sqrt(float64(dx*dx + dy*dy)))
+	if dist == 0 {
+		return
+	}
+	diff := (dist - segment) / dist * r.Stiffness
+	switch {
+	case pinnedA:
+		r.Points[b] = geometry.Point{X: pb.X - dx*diff, Y: pb.Y - dy*diff}
+	case pinnedB:
+		r.Points[a] = geometry.Point{X: pa.X + dx*diff, Y: pa.Y + dy*diff}
+	default:
+		r.Points[a] = geometry.Point{X: pa.X + dx*diff*0.5, Y: pa.Y + dy*diff*0.5}
+		r.Points[b] = geometry.Point{X: pb.X - dx*diff*0.5, Y: pb.Y - dy*diff*0.5}
+	}
+}
+
+// Stretch returns how much longer is the Rope than its Length, 0 if is not stretched
+func (r Rope) Stretch() float32 {
+	total := float32(0)
+	for i := 1; i < len(r.Points); i++ {
+		total += distance(r.Points[i-1], r.Points[i])
+	}
+	if total <= r.Length {
+		return 0
+	}
+	return total - r.Length
+}
+
+// Pull returns the direction that the Rope pulls its end
+func (r Rope) Pull() geometry.Point {
+	last := len(r.Points) - 1
+	end := r.Points[last]
+	to := r.Points[last-1]
+	dist := distance(end, to)
+	if dist == 0 {
+		return geometry.Point{}
+	}
+	return geometry.Point{
+		X: (to.X - end.X) / dist,
+		Y: (to.Y - end.Y) / dist,
+	}
+}
+
+// distance between two points
+func distance(a, b geometry.Point) float32 {
+	dx := b.X - a.X
+	dy := b.Y - a.Y
+	return float32(math.Sqrt(float64(dx*dx + dy*dy)))
+}
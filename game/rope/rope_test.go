@@ -0,0 +1,117 @@
+/*
+ * Copyright (c) 2020 Juan Medina.
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package rope
+
+import (
+	"fmt"
+	"github.com/juan-medina/gosge/components/geometry"
+	"testing"
+)
+
+func TestRope_Update(t *testing.T) {
+	from := geometry.Point{X: 0, Y: 0}
+	to := geometry.Point{X: 100, Y: 0}
+
+	r := New(from, to, 4, 120, 1, 500)
+
+	for i := 0; i < 60; i++ {
+		r.Update(from, to, 1.0/60, nil)
+	}
+
+	// the ends are pinned
+	if got := r.Points[0]; got != from {
+		t.Fatalf("rope start error, got %v, expect %v", got, from)
+	}
+	if got := r.Points[4]; got != to {
+		t.Fatalf("rope end error, got %v, expect %v", got, to)
+	}
+
+	// a loose rope sag
+	if got := r.Points[2].Y; got <= 0 {
+		t.Fatalf("rope sag error, got %v, expect > %v", got, 0)
+	}
+}
+
+func TestRope_Snag(t *testing.T) {
+	from := geometry.Point{X: 0, Y: 0}
+	to := geometry.Point{X: 100, Y: 0}
+
+	r := New(from, to, 4, 120, 1, 500)
+
+	blocked := func(p geometry.Point) bool {
+		return p.Y > 5
+	}
+
+	snags := 0
+	before := make([]geometry.Point, len(r.Points))
+	for i := 0; i < 60; i++ {
+		copy(before, r.Points)
+		snags = r.Update(from, to, 1.0/60, blocked)
+	}
+
+	if snags == 0 {
+		t.Fatalf("rope snag error, got %v, expect > %v", snags, 0)
+	}
+
+	// snagged points do not move
+	for i, p := range r.Points {
+		if r.snagged[i] && p != before[i] {
+			t.Fatalf("rope snag point %d error, got %v, expect %v", i, p, before[i])
+		}
+	}
+}
+
+func TestRope_Stretch(t *testing.T) {
+	type tc struct {
+		length float32
+		to     geometry.Point
+		expect float32
+	}
+
+	cases := []tc{
+		{length: 100, to: geometry.Point{X: 50}, expect: 0},
+		{length: 100, to: geometry.Point{X: 100}, expect: 0},
+		{length: 100, to: geometry.Point{X: 150}, expect: 50},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("case %d", i+1), func(t *testing.T) {
+			r := New(geometry.Point{}, c.to, 4, c.length, 1, 0)
+			got := r.Stretch()
+
+			diff := got - c.expect
+			if diff < -0.0001 || diff > 0.0001 {
+				t.Fatalf("stretch error, got %v, expect %v", got, c.expect)
+			}
+		})
+	}
+}
+
+func TestRope_Pull(t *testing.T) {
+	r := New(geometry.Point{X: 100}, geometry.Point{}, 4, 100, 1, 0)
+
+	expect := geometry.Point{X: 1, Y: 0}
+	if got := r.Pull(); got != expect {
+		t.Fatalf("pull error, got %v, expect %v", got, expect)
+	}
+}
@@ -34,6 +34,7 @@ import (
 	"github.com/juan-medina/mesh2prod/game/component"
 	"github.com/juan-medina/mesh2prod/game/constants"
 	"github.com/juan-medina/mesh2prod/game/movement"
+	"github.com/juan-medina/mesh2prod/game/pause"
 	"github.com/juan-medina/mesh2prod/game/winning"
 	"math"
 	"reflect"
@@ -66,12 +67,14 @@ const (
 )
 
 type scoreSystem struct {
+	pause.State
 	gs        geometry.Scale // game scale
 	dr        geometry.Size  // design resolution
 	total     int            // total points
 	lastScore int            // last score
 	toAdd     int            // score to add
 	toSub     int            // score to sub
+	cleared   int            // blocks that has been clear
 	textLabel *goecs.Entity  // our text
 	end       bool
 }
@@ -148,6 +151,9 @@ func (ss *scoreSystem) load(eng *gosge.Engine) error {
 	// text fade system
 	world.AddSystem(ss.textFadeSystem)
 
+	// keep track of the pause
+	ss.Track(world)
+
 	// listen to level events
 	world.AddListener(ss.levelEvents, winning.LevelEndEventType)
 
@@ -165,6 +171,8 @@ func (ss *scoreSystem) pointsListener(world *goecs.World, signal interface{}, _
 		extra := 0
 
 		if e.Total > 0 {
+			// count the blocks clear
+			ss.cleared += e.Total
 			// base points
 			base = e.Total * pointPerBlock
 			// multiply by 1 per each 4 blocks
@@ -183,13 +191,18 @@ func (ss *scoreSystem) pointsListener(world *goecs.World, signal interface{}, _
 		}
 
 		ss.addFloatPoints(world, base, extra, e.At)
+
+		// if our balance goes under our debt we are bankrupt
+		if ss.total+ss.toAdd-ss.toSub < -winning.MaxDebt {
+			world.Signal(winning.RollbackEvent{Cause: winning.Bankrupt})
+		}
 	}
 	return nil
 }
 
 // update the points
 func (ss *scoreSystem) pointsDisplaySystem(_ *goecs.World, delta float32) error {
-	if ss.end {
+	if ss.end || ss.IsPaused() {
 		return nil
 	}
 	// if we have points to add
@@ -222,6 +235,9 @@ func (ss *scoreSystem) pointsDisplaySystem(_ *goecs.World, delta float32) error
 
 // fate scroll text
 func (ss *scoreSystem) textFadeSystem(world *goecs.World, delta float32) error {
+	if ss.IsPaused() {
+		return nil
+	}
 	// get any text that is moving
 	for it := world.Iterator(ui.TYPE.Text, color.TYPE.Solid, movement.Type, component.TYPE.FloatText); it != nil; it = it.Next() {
 		ent := it.Value()
@@ -278,6 +294,7 @@ func (ss *scoreSystem) addFloatPoints(world *goecs.World, base, extra int, at ge
 				Y: -textScrollSpeedY * ss.gs.Max,
 			},
 		},
+		movement.Scroll{},
 		component.FloatText{},
 	)
 }
@@ -297,7 +314,7 @@ func (ss *scoreSystem) levelEvents(world *goecs.World, signal interface{}, _ flo
 		text.String = fmt.Sprintf("%d", ss.lastScore)
 
 		ss.textLabel.Set(text)
-		world.Signal(winning.FinalScoreEvent{Total: ss.total})
+		world.Signal(winning.FinalScoreEvent{Total: ss.total, Cleared: ss.cleared})
 	}
 
 	return nil
@@ -0,0 +1,144 @@
+/*
+ * Copyright (c) 2020 Juan Medina.
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package skin
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/juan-medina/gosge/components/color"
+	"github.com/juan-medina/gosge/components/geometry"
+	"github.com/juan-medina/gosge/options"
+	"github.com/juan-medina/mesh2prod/game/constants"
+	"io/ioutil"
+)
+
+// Skin is the animation for a skin
+type Skin struct {
+	Name   string      `json:"name"`   // Name to display
+	Sheet  string      `json:"sheet"`  // Sheet is the sprite sheet
+	Base   string      `json:"base"`   // Base is the animation base, with the frame number as %d
+	Frames int32       `json:"frames"` // Frames is the number of frames in the animation
+	Delay  float32     `json:"delay"`  // Delay number of seconds to wait in each frame
+	Tint   color.Solid `json:"tint"`   // Tint for the sprite, none if is transparent
+}
+
+// Color returns the Skin tint, or white if it does not have any
+func (s Skin) Color() color.Solid {
+	if s.Tint.A == 0 {
+		return color.White
+	}
+	return s.Tint
+}
+
+// Plane is a Skin for the plane, with its geometry
+type Plane struct {
+	Skin
+	Joint geometry.Point `json:"joint"` // Joint shift, X from the back and Y from the center, of the rope
+	Gun   geometry.Point `json:"gun"`   // Gun shift from the center
+}
+
+// Payload is a Skin for the payload that the plane carries, with its geometry
+type Payload struct {
+	Skin
+	Joint       float32 `json:"joint"`       // Joint shift in X from the front, of the ropes
+	JointTop    float32 `json:"jointTop"`    // JointTop shift in Y from the center, of the top rope
+	JointBottom float32 `json:"jointBottom"` // JointBottom shift in Y from the center, of the bottom rope
+}
+
+// Catalogue is the available skins
+type Catalogue struct {
+	Planes   []Plane   `json:"planes"`   // Planes skins
+	Payloads []Payload `json:"payloads"` // Payloads skins
+}
+
+// Load the Catalogue from a file
+func Load(fileName string) (Catalogue, error) {
+	var c Catalogue
+
+	data, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return c, err
+	}
+
+	if err = json.Unmarshal(data, &c); err != nil {
+		return c, err
+	}
+
+	if len(c.Planes) == 0 || len(c.Payloads) == 0 {
+		return c, errors.New("skins catalogue need at least a plane and a payload")
+	}
+
+	return c, nil
+}
+
+// Plane returns the Plane skin with a name, or the first one if it does not exist
+func (c Catalogue) Plane(name string) Plane {
+	for _, p := range c.Planes {
+		if p.Name == name {
+			return p
+		}
+	}
+	return c.Planes[0]
+}
+
+// Payload returns the Payload skin with a name, or the first one if it does not exist
+func (c Catalogue) Payload(name string) Payload {
+	for _, p := range c.Payloads {
+		if p.Name == name {
+			return p
+		}
+	}
+	return c.Payloads[0]
+}
+
+// Sheets returns the sprite sheets used by the skins, without repeating them
+func (c Catalogue) Sheets() []string {
+	sheets := make([]string, 0)
+	found := make(map[string]bool)
+	add := func(sheet string) {
+		if !found[sheet] {
+			found[sheet] = true
+			sheets = append(sheets, sheet)
+		}
+	}
+	for _, p := range c.Planes {
+		add(p.Sheet)
+	}
+	for _, p := range c.Payloads {
+		add(p.Sheet)
+	}
+	return sheets
+}
+
+// Selected returns the Plane and Payload skins selected in the settings
+func (c Catalogue) Selected(settings options.Settings) (Plane, Payload) {
+	plane := c.Plane(settings.GetString(constants.PlaneSkinConfig, ""))
+	payload := c.Payload(settings.GetString(constants.PayloadSkinConfig, ""))
+	return plane, payload
+}
+
+// Select a Plane and Payload skin in the settings
+func Select(settings options.Settings, plane Plane, payload Payload) {
+	settings.SetString(constants.PlaneSkinConfig, plane.Name)
+	settings.SetString(constants.PayloadSkinConfig, payload.Name)
+}
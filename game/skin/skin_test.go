@@ -0,0 +1,93 @@
+/*
+ * Copyright (c) 2020 Juan Medina.
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package skin
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	c, err := Load("../../resources/skins/skins.json")
+	if err != nil {
+		t.Fatalf("load error, got %v, expect nil", err)
+	}
+
+	if got := c.Plane("").Base; got != "gopher_plane_%d.png" {
+		t.Fatalf("default plane error, got %v, expect %v", got, "gopher_plane_%d.png")
+	}
+
+	if got := c.Payload("").Base; got != "box%d.png" {
+		t.Fatalf("default payload error, got %v, expect %v", got, "box%d.png")
+	}
+
+	if _, err = Load("not_found.json"); err == nil {
+		t.Fatalf("load error, got nil, expect error")
+	}
+}
+
+func TestCatalogue_Plane(t *testing.T) {
+	c := Catalogue{
+		Planes: []Plane{
+			{Skin: Skin{Name: "first"}},
+			{Skin: Skin{Name: "second"}},
+		},
+	}
+
+	type tc struct {
+		name   string
+		expect string
+	}
+
+	cases := []tc{
+		{name: "first", expect: "first"},
+		{name: "second", expect: "second"},
+		{name: "unknown", expect: "first"},
+		{name: "", expect: "first"},
+	}
+
+	for i, v := range cases {
+		t.Run(fmt.Sprintf("case %d", i+1), func(t *testing.T) {
+			if got := c.Plane(v.name).Name; got != v.expect {
+				t.Fatalf("plane error, got %v, expect %v", got, v.expect)
+			}
+		})
+	}
+}
+
+func TestCatalogue_Sheets(t *testing.T) {
+	c := Catalogue{
+		Planes: []Plane{
+			{Skin: Skin{Sheet: "a.json"}},
+			{Skin: Skin{Sheet: "b.json"}},
+		},
+		Payloads: []Payload{
+			{Skin: Skin{Sheet: "a.json"}},
+		},
+	}
+
+	got := c.Sheets()
+	if len(got) != 2 || got[0] != "a.json" || got[1] != "b.json" {
+		t.Fatalf("sheets error, got %v, expect %v", got, []string{"a.json", "b.json"})
+	}
+}
@@ -23,49 +23,111 @@
 package target
 
 import (
+	"fmt"
 	"github.com/juan-medina/goecs"
 	"github.com/juan-medina/gosge"
 	"github.com/juan-medina/gosge/components/animation"
 	"github.com/juan-medina/gosge/components/color"
-	"github.com/juan-medina/gosge/components/device"
 	"github.com/juan-medina/gosge/components/effects"
 	"github.com/juan-medina/gosge/components/geometry"
 	"github.com/juan-medina/gosge/components/shapes"
 	"github.com/juan-medina/gosge/components/sprite"
+	"github.com/juan-medina/gosge/components/ui"
 	"github.com/juan-medina/gosge/events"
 	"github.com/juan-medina/mesh2prod/game/component"
 	"github.com/juan-medina/mesh2prod/game/constants"
+	"github.com/juan-medina/mesh2prod/game/gamemap"
+	"github.com/juan-medina/mesh2prod/game/input"
 	"github.com/juan-medina/mesh2prod/game/movement"
+	"github.com/juan-medina/mesh2prod/game/pause"
 	"github.com/juan-medina/mesh2prod/game/plane"
+	"github.com/juan-medina/mesh2prod/game/powerup"
+	"github.com/juan-medina/mesh2prod/game/upgrade"
+	"github.com/juan-medina/mesh2prod/game/weapon"
 	"github.com/juan-medina/mesh2prod/game/winning"
 	"math"
+	"reflect"
 )
 
 // logic constants
 const (
-	markSprite        = "mark.png"                 // mark sprite
-	bulletSprite      = "bullet_%d.png"            // bullet sprite base
-	bulletScale       = 0.25                       // scale for the bullet sprite
-	bulletFrames      = 5                          // bullet frames
-	bulletFramesDelay = 0.065                      // bullet frame delay
-	bulletSpeed       = 600                        // bullet speed
-	targetScale       = 0.5                        // block scale
-	targetGapX        = 100                        // target gap from gun pos
-	shotSound         = "resources/audio/shot.wav" // plane shot sound
+	markSprite        = "mark.png"                       // mark sprite
+	bulletSprite      = "bullet_%d.png"                  // bullet sprite base
+	bulletScale       = 0.25                             // scale for the bullet sprite
+	bulletFrames      = 5                                // bullet frames
+	bulletFramesDelay = 0.065                            // bullet frame delay
+	bulletSpeed       = 600                              // bullet speed
+	targetScale       = 0.5                              // block scale
+	targetGapX        = 100                              // target gap from gun pos
+	shotSound         = "resources/audio/shot.wav"       // plane shot sound
+	spreadGap         = 60                               // vertical gap between spread shot bullets
+	font              = "resources/fonts/go_regular.fnt" // our text font
+	fontSize          = 30                               // text font size
+	overheatSound     = "resources/audio/overheat.wav"   // weapon overheat sound
+	overheatRecover   = 0.25                             // heat that we need to cool down to after overheating
+	rapidFireFactor   = 2                                // how much faster we fire with rapid fire
+	heatBarWidth      = 200                              // heat bar width
+	heatBarHeight     = 15                               // heat bar height
+	freeAimSpeed      = 600                              // free aim reticle vertical speed
+	freeAimDistance   = 600                              // free aim reticle distance from gun pos when there is no block
+	bulletLife        = 4                                // bullet life in seconds
+	minFlightTime     = 0.1                              // min bullet flight time to the target
+	pathSegments      = 16                               // segments in the predicted trajectory
 )
 
 var (
-	bulletColor = color.Red.Alpha(180) // bullet color
+	heatColors = ui.ProgressBarColor{
+		Gradient: color.Gradient{
+			From:      color.White,
+			To:        color.Orange,
+			Direction: color.GradientHorizontal,
+		},
+		Border: color.DarkGray,
+		Empty:  color.Gray.Alpha(120),
+	} // heat bar colors
+	overheatColors = ui.ProgressBarColor{
+		Gradient: color.Gradient{
+			From:      color.Orange,
+			To:        color.Red,
+			Direction: color.GradientHorizontal,
+		},
+		Border: color.Red,
+		Empty:  color.Gray.Alpha(120),
+	} // heat bar colors when overheated
+	modeColors = map[input.Targeting]color.Solid{
+		input.Lock:      color.Red,
+		input.SmartLock: color.Gold,
+		input.FreeAim:   color.SkyBlue,
+		input.Manual:    color.Red,
+	} // target and line colors for each targeting mode
 )
 
 type targetSystem struct {
-	gs         geometry.Scale // game scale
-	dr         geometry.Size  // design resolution
-	targetSize geometry.Size  // block size
-	gunPos     geometry.Point // plane gun position
-	target     *goecs.Entity  // current target position
-	line       *goecs.Entity  // target line
+	pause.State
+	gs         geometry.Scale  // game scale
+	dr         geometry.Size   // design resolution
+	targetSize geometry.Size   // block size
+	gunPos     geometry.Point  // plane gun position
+	target     *goecs.Entity   // current target position
+	path       []*goecs.Entity // predicted trajectory segments
 	end        bool
+	firing     bool            // is fire pressed
+	rapidFire  bool            // is rapid fire active
+	spread     bool            // is spread shot active
+	cooldown   float32         // time to the next shot
+	fireRate   float32         // fire rate upgrade factor
+	weapon     int             // current weapon
+	ammo       []int           // ammo for each weapon
+	hud        *goecs.Entity   // current weapon hud
+	heat       float32         // weapon heat, we overheat at 1
+	overheated bool            // are we cooling down after overheating
+	heatBar    *goecs.Entity   // heat bar
+	mode       input.Targeting // targeting mode
+	smart      *goecs.Entity   // block to target with smart lock
+	aimY       float32         // free aim reticle vertical position
+	aimMove    float32         // free aim reticle movement
+	aim        geometry.Point  // where we are aiming
+	hasAim     bool            // do we have something to aim at
 }
 
 // load the system
@@ -82,26 +144,100 @@ func (gms *targetSystem) load(eng *gosge.Engine) error {
 		return err
 	}
 
+	// pre-load font
+	if err = eng.LoadFont(font); err != nil {
+		return err
+	}
+
+	// overheat sound
+	if err = eng.LoadSound(overheatSound); err != nil {
+		return err
+	}
+
+	// get the targeting mode
+	gms.mode = input.Load(eng.GetSettings()).Targeting
+	gms.aimY = gms.dr.Height * 0.5 * gms.gs.Max
+
+	// apply the fire rate upgrade
+	gms.fireRate = upgrade.FireRate.Value(eng.GetSettings())
+
 	// get the world
 	world := eng.World()
 
 	// add the sprites from the current state
 	gms.addSprites(world)
 
+	// load the weapons ammo
+	gms.ammo = make([]int, len(weapon.Weapons))
+	for i, w := range weapon.Weapons {
+		gms.ammo[i] = w.Ammo()
+	}
+
+	// add the weapon hud
+	gms.hud = world.AddEntity(
+		ui.Text{
+			Size:       fontSize * gms.gs.Max,
+			Font:       font,
+			VAlignment: ui.BottomVAlignment,
+			HAlignment: ui.RightHAlignment,
+		},
+		geometry.Point{
+			X: (gms.dr.Width - 10) * gms.gs.Max,
+			Y: (gms.dr.Height - 10) * gms.gs.Max,
+		},
+		effects.Layer{Depth: -100},
+	)
+	gms.updateHud()
+
+	// add the heat bar, over the hud
+	gms.heatBar = world.AddEntity(
+		ui.ProgressBar{
+			Min:     0,
+			Max:     1,
+			Current: 0,
+		},
+		geometry.Point{
+			X: (gms.dr.Width - 10 - heatBarWidth) * gms.gs.Max,
+			Y: (gms.dr.Height - 15 - fontSize - heatBarHeight) * gms.gs.Max,
+		},
+		shapes.Box{
+			Size: geometry.Size{
+				Width:  heatBarWidth,
+				Height: heatBarHeight,
+			},
+			Scale:     gms.gs.Max,
+			Thickness: int32(gms.gs.Max),
+		},
+		heatColors,
+		effects.Layer{Depth: -100},
+	)
+
 	// add the target system that target blocks
 	world.AddSystem(gms.findTargetSystem)
 
 	// listen to plane changes
 	world.AddListener(gms.planeChanges, plane.PositionChangeEventType)
 
-	// listen to keys
-	world.AddListener(gms.keyListener, events.TYPE.KeyUpEvent)
+	// listen to actions
+	world.AddListener(gms.actionListener, input.ActionEventType)
+
+	// listen to free aim
+	world.AddListener(gms.aimListener, input.AimEventType)
 
-	// listen to gamepad
-	world.AddListener(gms.gamepadListener, events.TYPE.GamePadButtonUpEvent)
+	// listen to smart lock targets
+	world.AddListener(gms.smartListener, gamemap.SmartTargetEventType)
+
+	// keep track of the pause
+	gms.Track(world)
 
 	// listen to level events
-	world.AddListener(gms.levelEvents, winning.LevelEndEventType)
+	world.AddListener(gms.levelEvents, winning.LevelEndEventType, pause.StateEventType, powerup.StateEventType)
+
+	// listen to delayed shots
+	world.AddListener(gms.shotListener, shotEventType)
+
+	// weapon cooldown and rapid fire
+	world.AddSystem(gms.fireSystem)
 
 	return nil
 }
@@ -121,49 +257,194 @@ func (gms *targetSystem) addSprites(world *goecs.World) {
 		},
 		effects.Layer{Depth: 0},
 		effects.AlternateColor{
-			From:  color.Red,
-			To:    color.Red.Alpha(180),
+			From:  modeColors[gms.mode],
+			To:    modeColors[gms.mode].Alpha(180),
 			Time:  0.25,
 			Delay: 0,
 		},
 	)
 
-	// add the target line
-	gms.line = world.AddEntity(
-		geometry.Point{
-			X: 0,
-			Y: 0,
-		},
-		effects.AlternateColor{
-			From:  color.Red.Alpha(60),
-			To:    color.Red.Alpha(100),
-			Time:  0.35,
-			Delay: 0.35,
-		},
-		shapes.Line{
-			To:        geometry.Point{},
-			Thickness: 1.5 * gms.gs.Max,
-		},
-		effects.Layer{Depth: 0},
-	)
+	// add the predicted trajectory
+	gms.path = make([]*goecs.Entity, pathSegments)
+	for i := range gms.path {
+		gms.path[i] = world.AddEntity(
+			geometry.Point{
+				X: 0,
+				Y: 0,
+			},
+			effects.AlternateColor{
+				From:  modeColors[gms.mode].Alpha(60),
+				To:    modeColors[gms.mode].Alpha(100),
+				Time:  0.35,
+				Delay: 0.35,
+			},
+			shapes.Line{
+				To:        geometry.Point{},
+				Thickness: 1.5 * gms.gs.Max,
+			},
+			effects.Layer{Depth: 0},
+		)
+	}
 }
 
 // a system that target a block
-func (gms *targetSystem) findTargetSystem(world *goecs.World, _ float32) error {
+func (gms *targetSystem) findTargetSystem(world *goecs.World, delta float32) error {
 	if gms.end {
 		return nil
 	}
-	// get the line from
-	linePosFrom := geometry.Get.Point(gms.line)
-	linePosFrom.X = gms.gunPos.X
-	linePosFrom.Y = gms.gunPos.Y
-
-	// get the line component
-	line := shapes.Get.Line(gms.line)
 
 	// try to find a target
 	var found *goecs.Entity = nil
 
+	switch gms.mode {
+	case input.Manual:
+		// without assist we fire straight ahead, and we hide the target and the trajectory
+		gms.aim = geometry.Point{
+			X: gms.dr.Width * gms.gs.Max,
+			Y: gms.gunPos.Y,
+		}
+		gms.hasAim = true
+		gms.target.Set(geometry.Point{
+			X: -1000,
+			Y: -1000,
+		})
+		gms.hidePath()
+		return nil
+	case input.SmartLock:
+		// use the smart target if is still there
+		if gms.smart != nil && gms.smart.Contains(geometry.TYPE.Point) {
+			pos := geometry.Get.Point(gms.smart)
+			if pos.X-gms.gunPos.X > targetGapX*gms.gs.Max {
+				found = gms.smart
+			}
+		}
+		// otherwise lock as usual
+		if found == nil {
+			found = gms.closestBlock(world, gms.gunPos.Y)
+		}
+	case input.FreeAim:
+		// move the reticle
+		if !gms.IsPaused() {
+			gms.aimY += gms.aimMove * freeAimSpeed * gms.gs.Max * delta
+			if gms.aimY < 0 {
+				gms.aimY = 0
+			}
+			if maxY := gms.dr.Height * gms.gs.Max; gms.aimY > maxY {
+				gms.aimY = maxY
+			}
+		}
+		found = gms.closestBlock(world, gms.aimY)
+	default:
+		found = gms.closestBlock(world, gms.gunPos.Y)
+	}
+
+	// if we have no a target
+	if found == nil {
+		if gms.mode == input.FreeAim {
+			// with free aim, we aim to the reticle
+			gms.aim = geometry.Point{
+				X: gms.gunPos.X + (freeAimDistance * gms.gs.Max),
+				Y: gms.aimY,
+			}
+			gms.hasAim = true
+			gms.target.Set(gms.aim)
+			gms.updatePath(gms.aim)
+		} else {
+			gms.hasAim = false
+			// move target ouf ot screen
+			gms.target.Set(geometry.Point{
+				X: -1000,
+				Y: -1000,
+			})
+
+			// predict the trajectory straight from gun
+			gms.updatePath(geometry.Point{
+				X: gms.dr.Width * gms.gs.Max,
+				Y: gms.gunPos.Y,
+			})
+		}
+	} else {
+		pos := geometry.Get.Point(found)
+		targetPos := geometry.Point{
+			X: pos.X - (gms.targetSize.Width * gms.gs.Max * targetScale),
+			Y: pos.Y,
+		}
+		gms.aim = targetPos
+		gms.hasAim = targetPos.X > 0 && targetPos.Y > 0
+		gms.target.Set(targetPos)
+		gms.updatePath(targetPos)
+	}
+
+	return nil
+}
+
+// predict the trajectory of the current weapon from the gun to a position
+func (gms targetSystem) updatePath(to geometry.Point) {
+	prj, time := gms.launch(weapon.Weapons[gms.weapon], to)
+	bounds := gms.bounds()
+	points := prj.Predict(gms.gunPos, &bounds, time, len(gms.path), gms.gs.Max)
+	for i, segment := range gms.path {
+		line := shapes.Get.Line(segment)
+		line.To = points[i+1]
+		segment.Set(points[i])
+		segment.Set(line)
+	}
+}
+
+// hide the predicted trajectory
+func (gms targetSystem) hidePath() {
+	hidden := geometry.Point{
+		X: -1000,
+		Y: -1000,
+	}
+	for _, segment := range gms.path {
+		line := shapes.Get.Line(segment)
+		line.To = hidden
+		segment.Set(hidden)
+		segment.Set(line)
+	}
+}
+
+// the projectile that the gun launch with a weapon to reach a position, and the time that it takes
+func (gms targetSystem) launch(w weapon.Weapon, to geometry.Point) (movement.Projectile, float32) {
+	speed := bulletSpeed * gms.fireRate
+	diffX := (to.X - gms.gunPos.X) / gms.gs.Max
+	diffY := (to.Y - gms.gunPos.Y) / gms.gs.Max
+	time := diffX / speed
+	if time < minFlightTime {
+		time = minFlightTime
+	}
+	return movement.Projectile{
+		Velocity: geometry.Point{
+			X: speed,
+			// compensate the gravity so we still reach the position
+			Y: (diffY / time) - (w.Gravity() * time * 0.5),
+		},
+		Gravity: w.Gravity(),
+		Bounce:  w.Bounce(),
+		Life:    bulletLife,
+	}, time
+}
+
+// the bounds that projectiles ricochet off, the screen top and bottom edges, without horizontal range
+// so they fly off the screen sides
+func (gms targetSystem) bounds() movement.Constrain {
+	return movement.Constrain{
+		Min: geometry.Point{
+			X: 0,
+			Y: 0,
+		},
+		Max: geometry.Point{
+			X: 0,
+			Y: gms.dr.Height * gms.gs.Max,
+		},
+	}
+}
+
+// find the closest block on screen in a vertical position
+func (gms targetSystem) closestBlock(world *goecs.World, y float32) *goecs.Entity {
+	var found *goecs.Entity = nil
+
 	// half size of block
 	halfSize := gms.targetSize.Height * targetScale * gms.gs.Max * 0.5
 
@@ -185,7 +466,7 @@ func (gms *targetSystem) findTargetSystem(world *goecs.World, _ float32) error {
 		}
 
 		// difference in height
-		diffY := float32(math.Abs(float64(blockPos.Y - gms.gunPos.Y)))
+		diffY := float32(math.Abs(float64(blockPos.Y - y)))
 
 		// if we are under half block size
 		if diffY <= halfSize {
@@ -201,38 +482,7 @@ func (gms *targetSystem) findTargetSystem(world *goecs.World, _ float32) error {
 		}
 	}
 
-	// if we have no a target
-	if found == nil {
-		// move target ouf ot screen
-		gms.target.Set(geometry.Point{
-			X: -1000,
-			Y: -1000,
-		})
-
-		// move line straight from gun
-		line.To = geometry.Point{
-			X: gms.dr.Width * gms.gs.Max,
-			Y: gms.gunPos.Y,
-		}
-	} else {
-		pos := geometry.Get.Point(found)
-		targetPos := geometry.Point{
-			X: pos.X - (gms.targetSize.Width * gms.gs.Max * targetScale),
-			Y: pos.Y,
-		}
-		gms.target.Set(targetPos)
-		// calculate line pos
-		line.To = geometry.Point{
-			X: targetPos.X - (gms.targetSize.Width/2)*targetScale*gms.gs.Max,
-			Y: targetPos.Y,
-		}
-	}
-
-	// update line
-	gms.line.Set(linePosFrom)
-	gms.line.Set(line)
-
-	return nil
+	return found
 }
 
 // if the plane change position
@@ -248,109 +498,238 @@ func (gms *targetSystem) planeChanges(_ *goecs.World, signal interface{}, _ floa
 	return nil
 }
 
-// listen to keys
-func (gms *targetSystem) keyListener(world *goecs.World, signal interface{}, _ float32) error {
-	if gms.end {
+// listen to actions
+func (gms *targetSystem) actionListener(world *goecs.World, signal interface{}, _ float32) error {
+	if gms.end || gms.IsPaused() {
 		return nil
 	}
 	switch e := signal.(type) {
-	// if we got a key up
-	case events.KeyUpEvent:
-		// if it space
-		if e.Key == device.KeySpace {
-			gms.createBullet(world)
+	// if we got an action
+	case input.ActionEvent:
+		switch e.Action {
+		case input.Fire:
+			// we keep firing while is pressed
+			gms.firing = e.Pressed
+			if gms.firing {
+				gms.fire(world)
+			}
+		case input.SwitchWeapon:
+			if !e.Pressed {
+				gms.switchWeapon()
+			}
+		}
+	}
+	return nil
+}
+
+// move the free aim reticle with the stick
+func (gms *targetSystem) aimListener(_ *goecs.World, signal interface{}, _ float32) error {
+	switch e := signal.(type) {
+	case input.AimEvent:
+		gms.aimMove = e.Amount
+	}
+	return nil
+}
+
+// keep track of the smart lock target
+func (gms *targetSystem) smartListener(_ *goecs.World, signal interface{}, _ float32) error {
+	switch e := signal.(type) {
+	case gamemap.SmartTargetEvent:
+		gms.smart = e.Block
+	}
+	return nil
+}
+
+// cool down the weapon, and keep firing while fire is pressed
+func (gms *targetSystem) fireSystem(world *goecs.World, delta float32) error {
+	if gms.end || gms.IsPaused() {
+		return nil
+	}
+	if gms.cooldown > 0 {
+		gms.cooldown -= delta
+	}
+	if gms.heat > 0 {
+		if gms.heat -= weapon.Weapons[gms.weapon].Cooling() * delta; gms.heat < 0 {
+			gms.heat = 0
 		}
+		// we could fire again when we cool down enough
+		if gms.overheated && gms.heat <= overheatRecover {
+			gms.overheated = false
+			gms.heatBar.Set(heatColors)
+			gms.hud.Remove(effects.TYPE.AlternateColor)
+			gms.hud.Remove(effects.TYPE.AlternateColorState)
+			gms.updateHud()
+		}
+		bar := ui.Get.ProgressBar(gms.heatBar)
+		bar.Current = gms.heat
+		gms.heatBar.Set(bar)
+	}
+	if gms.firing {
+		gms.fire(world)
 	}
 	return nil
 }
 
-func (gms *targetSystem) gamepadListener(world *goecs.World, signal interface{}, _ float32) error {
+// fire the current weapon, if it has cool down and is not overheated
+func (gms *targetSystem) fire(world *goecs.World) {
+	if gms.cooldown > 0 || gms.overheated {
+		return
+	}
+
+	// if we do not have anything to aim at
+	if !gms.hasAim {
+		return
+	}
+
+	w := weapon.Weapons[gms.weapon]
+	for _, shot := range w.Pattern() {
+		if shot.Delay > 0 {
+			world.Signal(events.DelaySignal{
+				Signal: shotEvent{weapon: w, shot: shot},
+				Time:   shot.Delay,
+			})
+		} else {
+			gms.shoot(world, w, shot)
+		}
+	}
+	gms.cooldown = w.Delay() / gms.fireRate
+	if gms.rapidFire {
+		gms.cooldown /= rapidFireFactor
+	}
+
+	// heat the weapon
+	if gms.heat += w.Heat(); gms.heat >= 1 {
+		gms.overheat(world)
+	}
+
+	// use the ammo, and switch weapon if we run out of it
+	if gms.ammo[gms.weapon] != weapon.Unlimited {
+		if gms.ammo[gms.weapon]--; gms.ammo[gms.weapon] == 0 {
+			gms.switchWeapon()
+		}
+	}
+	gms.updateHud()
+}
+
+// fire the delayed shots
+func (gms *targetSystem) shotListener(world *goecs.World, signal interface{}, _ float32) error {
 	if gms.end {
 		return nil
 	}
 	switch e := signal.(type) {
-	// if we got a key up
-	case events.GamePadButtonUpEvent:
-		// if it space
-		if e.Button == device.GamepadButton3 {
-			gms.createBullet(world)
-		}
+	case shotEvent:
+		gms.shoot(world, e.weapon, e.shot)
 	}
 	return nil
 }
 
-func (gms targetSystem) createBullet(world *goecs.World) {
-	// get target
-	targetPos := geometry.Get.Point(gms.target)
-	// if we have a target on the screen
-	if targetPos.X > 0 && targetPos.Y > 0 {
-		// calculate min / max y and velocity
-		minY := gms.gunPos.Y
-		maxY := targetPos.Y
-		velY := maxY - minY
-		velY = float32(float64(velY)/math.Abs(float64(velY))) * bulletSpeed * 10
-		if minY > maxY {
-			aux := minY
-			minY = maxY
-			maxY = aux
+// shoot a weapon shot to the target
+func (gms targetSystem) shoot(world *goecs.World, w weapon.Weapon, shot weapon.Shot) {
+	// if we have something to aim at
+	if gms.hasAim {
+		targetY := gms.aim.Y + (float32(shot.Row) * gms.targetSize.Height * targetScale * gms.gs.Max)
+		// add a bullet to the target
+		gms.addBullet(world, w, targetY)
+		// with spread shot add one above and one below
+		if gms.spread {
+			gms.addBullet(world, w, targetY-(spreadGap*gms.gs.Max))
+			gms.addBullet(world, w, targetY+(spreadGap*gms.gs.Max))
 		}
-		// add a bullet
-		world.AddEntity(
-			animation.Animation{
-				Sequences: map[string]animation.Sequence{
-					"moving": {
-						Sheet:  constants.SpriteSheet,
-						Base:   bulletSprite,
-						Scale:  gms.gs.Max * bulletScale,
-						Frames: bulletFrames,
-						Delay:  bulletFramesDelay,
-					},
-				},
-				Current: "moving",
-				Speed:   1,
-			},
-			gms.gunPos,
-			movement.Movement{
-				Amount: geometry.Point{
-					Y: velY * gms.gs.Max,
-					X: bulletSpeed * gms.gs.Max,
-				},
-			},
-			movement.Constrain{
-				Min: geometry.Point{
-					X: 0,
-					Y: minY,
-				},
-				Max: geometry.Point{
-					X: gms.dr.Width * gms.gs.Max,
-					Y: maxY,
-				},
-			},
-			bulletColor,
-			component.Bullet{},
-			effects.Layer{Depth: 0},
-		)
 		world.Signal(events.PlaySoundEvent{Name: shotSound, Volume: 1})
 	}
 }
 
-func (gms *targetSystem) bulletSystem(world *goecs.World, _ float32) error {
-	for it := world.Iterator(component.TYPE.Bullet, geometry.TYPE.Point); it != nil; it = it.Next() {
-		bullet := it.Value()
-		pos := geometry.Get.Point(bullet)
-		if pos.X >= gms.dr.Width*gms.gs.Max {
-			_ = world.Remove(bullet)
+// lock the weapon until it cool down
+func (gms *targetSystem) overheat(world *goecs.World) {
+	gms.heat = 1
+	gms.overheated = true
+	gms.heatBar.Set(overheatColors)
+	gms.hud.Set(effects.AlternateColor{
+		From:  color.Red,
+		To:    color.White,
+		Time:  0.15,
+		Delay: 0,
+	})
+	gms.updateHud()
+	world.Signal(events.PlaySoundEvent{Name: overheatSound, Volume: 1})
+}
+
+// switch to the next weapon that has ammo
+func (gms *targetSystem) switchWeapon() {
+	for i := 1; i <= len(weapon.Weapons); i++ {
+		next := (gms.weapon + i) % len(weapon.Weapons)
+		if gms.ammo[next] != 0 {
+			gms.weapon = next
+			break
 		}
 	}
-	return nil
+	gms.updateHud()
+}
+
+// update the hud with the current weapon and its ammo, or if we are overheated
+func (gms targetSystem) updateHud() {
+	text := ui.Get.Text(gms.hud)
+	if gms.overheated {
+		text.String = "Overheat!"
+		gms.hud.Set(text)
+		return
+	}
+	w := weapon.Weapons[gms.weapon]
+	text.String = w.Name()
+	if gms.ammo[gms.weapon] != weapon.Unlimited {
+		text.String = fmt.Sprintf("%s %d", w.Name(), gms.ammo[gms.weapon])
+	}
+	gms.hud.Set(text)
+	gms.hud.Set(w.Color().Alpha(255))
+}
+
+func (gms targetSystem) addBullet(world *goecs.World, w weapon.Weapon, targetY float32) {
+	prj, _ := gms.launch(w, geometry.Point{X: gms.aim.X, Y: targetY})
+	// add a bullet
+	world.AddEntity(
+		animation.Animation{
+			Sequences: map[string]animation.Sequence{
+				"moving": {
+					Sheet:  constants.SpriteSheet,
+					Base:   bulletSprite,
+					Scale:  gms.gs.Max * bulletScale,
+					Frames: bulletFrames,
+					Delay:  bulletFramesDelay,
+				},
+			},
+			Current: "moving",
+			Speed:   1,
+		},
+		gms.gunPos,
+		prj,
+		gms.bounds(),
+		w.Color(),
+		component.Bullet{Effect: int(w.Effect()), Piercing: w.Piercing()},
+		effects.Layer{Depth: 0},
+		movement.Drift{},
+	)
 }
 
 func (gms *targetSystem) levelEvents(world *goecs.World, signal interface{}, _ float32) error {
-	switch signal.(type) {
+	switch e := signal.(type) {
+	case pause.StateEvent:
+		// stop, so we do not keep firing if fire is released while paused
+		gms.firing = false
+	case powerup.StateEvent:
+		switch e.Kind {
+		case powerup.RapidFire:
+			gms.rapidFire = e.Active
+		case powerup.SpreadShot:
+			gms.spread = e.Active
+		}
 	case winning.LevelEndEvent:
 		gms.end = true
-		_ = world.Remove(gms.line)
+		for _, segment := range gms.path {
+			_ = world.Remove(segment)
+		}
 		_ = world.Remove(gms.target)
+		_ = world.Remove(gms.hud)
+		_ = world.Remove(gms.heatBar)
 	}
 
 	return nil
@@ -364,3 +743,10 @@ func System(engine *gosge.Engine, gs geometry.Scale, dr geometry.Size) error {
 	}
 	return gms.load(engine)
 }
+
+type shotEvent struct {
+	weapon weapon.Weapon
+	shot   weapon.Shot
+}
+
+var shotEventType = reflect.TypeOf(shotEvent{})
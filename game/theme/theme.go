@@ -0,0 +1,116 @@
+/*
+ * Copyright (c) 2020 Juan Medina.
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package theme
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/juan-medina/gosge/components/color"
+	"github.com/juan-medina/mesh2prod/game/constants"
+	"github.com/juan-medina/mesh2prod/game/level"
+	"io/ioutil"
+)
+
+// Sky is the sky gradient, from the top to the bottom
+type Sky struct {
+	From color.Solid `json:"from"` // From is the top color
+	To   color.Solid `json:"to"`   // To is the bottom color
+}
+
+// Gradient returns the Sky as a vertical color.Gradient
+func (s Sky) Gradient() color.Gradient {
+	return color.Gradient{
+		From:      s.From,
+		To:        s.To,
+		Direction: color.GradientVertical,
+	}
+}
+
+// Theme is the look of a level, its sky, clouds and blocks
+type Theme struct {
+	Name       string        `json:"name"`       // Name to display
+	Sky        Sky           `json:"sky"`        // Sky gradient
+	Sheet      string        `json:"sheet"`      // Sheet is the sprite sheet for the clouds
+	Clouds     []string      `json:"clouds"`     // Clouds sprites
+	CloudTint  color.Solid   `json:"cloudTint"`  // CloudTint for the clouds, the alpha is set per layer
+	Layers     int           `json:"layers"`     // Layers of clouds
+	PerLayer   int           `json:"perLayer"`   // PerLayer is the number of clouds in each layer
+	MinSpeed   float32       `json:"minSpeed"`   // MinSpeed of the furthest clouds layer
+	LayerSpeed float32       `json:"layerSpeed"` // LayerSpeed is the speed added to each closer layer
+	Palette    []color.Solid `json:"palette"`    // Palette for the map blocks
+}
+
+// Catalogue is the available themes, and the ones pick by each cloud size and level
+type Catalogue struct {
+	Themes []Theme           `json:"themes"` // Themes available, the first is the default
+	Clouds map[string]string `json:"clouds"` // Clouds is the theme name for each cloud size name
+	Levels map[string]string `json:"levels"` // Levels is the theme name for a level key, over its cloud size
+}
+
+// Load the Catalogue from a file
+func Load(fileName string) (Catalogue, error) {
+	var c Catalogue
+
+	data, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return c, err
+	}
+
+	if err = json.Unmarshal(data, &c); err != nil {
+		return c, err
+	}
+
+	if len(c.Themes) == 0 {
+		return c, errors.New("themes catalogue need at least a theme")
+	}
+
+	for _, t := range c.Themes {
+		if len(t.Clouds) == 0 || len(t.Palette) == 0 {
+			return c, fmt.Errorf("theme %q need clouds and a palette", t.Name)
+		}
+		if t.Layers <= 0 || t.PerLayer <= 0 {
+			return c, fmt.Errorf("theme %q need layers of clouds", t.Name)
+		}
+	}
+
+	return c, nil
+}
+
+// Theme returns the Theme with a name, or the first one if it does not exist
+func (c Catalogue) Theme(name string) Theme {
+	for _, t := range c.Themes {
+		if t.Name == name {
+			return t
+		}
+	}
+	return c.Themes[0]
+}
+
+// For returns the Theme for a level, by its key or its cloud size
+func (c Catalogue) For(lvl level.Level) Theme {
+	if name, ok := c.Levels[lvl.Key()]; ok {
+		return c.Theme(name)
+	}
+	return c.Theme(c.Clouds[constants.CloudNames[lvl.Cloud]])
+}
@@ -0,0 +1,92 @@
+/*
+ * Copyright (c) 2020 Juan Medina.
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package theme
+
+import (
+	"fmt"
+	"github.com/juan-medina/mesh2prod/game/constants"
+	"github.com/juan-medina/mesh2prod/game/level"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	c, err := Load("../../resources/themes/themes.json")
+	if err != nil {
+		t.Fatalf("load error, got %v, expect nil", err)
+	}
+
+	if got := c.Theme("").Name; got != "Blue Sky" {
+		t.Fatalf("default theme error, got %v, expect %v", got, "Blue Sky")
+	}
+
+	for _, cs := range []constants.CloudSize{constants.LocalCloud, constants.StartupCloud,
+		constants.CorpCloud, constants.PublicCloud} {
+		name := c.Clouds[constants.CloudNames[cs]]
+		if got := c.Theme(name).Name; got != name {
+			t.Fatalf("cloud theme error, got %v, expect %v", got, name)
+		}
+	}
+
+	if _, err = Load("not_found.json"); err == nil {
+		t.Fatalf("load error, got nil, expect error")
+	}
+}
+
+func TestCatalogue_For(t *testing.T) {
+	c := Catalogue{
+		Themes: []Theme{
+			{Name: "default"},
+			{Name: "local"},
+			{Name: "night"},
+		},
+		Clouds: map[string]string{
+			"local": "local",
+		},
+		Levels: map[string]string{
+			"public_prod": "night",
+			"local_prod":  "night",
+		},
+	}
+
+	type tc struct {
+		cloud  constants.CloudSize
+		env    level.Environment
+		expect string
+	}
+
+	cases := []tc{
+		{cloud: constants.LocalCloud, env: level.Dev, expect: "local"},
+		{cloud: constants.LocalCloud, env: level.Prod, expect: "night"},
+		{cloud: constants.PublicCloud, env: level.Staging, expect: "default"},
+		{cloud: constants.PublicCloud, env: level.Prod, expect: "night"},
+		{cloud: constants.CorpCloud, env: level.Prod, expect: "default"},
+	}
+
+	for i, v := range cases {
+		t.Run(fmt.Sprintf("case %d", i+1), func(t *testing.T) {
+			if got := c.For(level.Get(v.cloud, v.env)).Name; got != v.expect {
+				t.Fatalf("theme error, got %v, expect %v", got, v.expect)
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+/*
+ * Copyright (c) 2020 Juan Medina.
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package timeline
+
+import (
+	"github.com/juan-medina/goecs"
+	"github.com/juan-medina/gosge"
+	"github.com/juan-medina/gosge/components/color"
+	"github.com/juan-medina/gosge/components/effects"
+	"github.com/juan-medina/gosge/components/geometry"
+	"github.com/juan-medina/gosge/components/ui"
+	"github.com/juan-medina/gosge/events"
+	"github.com/juan-medina/mesh2prod/game/winning"
+	"reflect"
+)
+
+// logic constants
+const (
+	font        = "resources/fonts/go_regular.fnt" // message font
+	fontSize    = 60                               // message font size
+	messageTime = 3                                // seconds that a message is displayed
+)
+
+// MarkEvent is trigger when the level progress pass a Mark in the Timeline
+type MarkEvent struct {
+	Mark Mark
+}
+
+// MarkEventType is the reflect.Type of MarkEvent
+var MarkEventType = reflect.TypeOf(MarkEvent{})
+
+// removeMessageEvent is a signal to remove a message
+type removeMessageEvent struct {
+	ent *goecs.Entity
+}
+
+// removeMessageEventType is the reflect.Type of removeMessageEvent
+var removeMessageEventType = reflect.TypeOf(removeMessageEvent{})
+
+type timelineSystem struct {
+	gs       geometry.Scale
+	dr       geometry.Size
+	timeline Timeline
+	end      bool
+}
+
+// load the system
+func (ts *timelineSystem) load(eng *gosge.Engine) error {
+	// pre-load font
+	if err := eng.LoadFont(font); err != nil {
+		return err
+	}
+
+	world := eng.World()
+
+	// listen to the progress
+	world.AddListener(ts.progressListener, winning.ProgressEventType)
+
+	// listen to our marks
+	world.AddListener(ts.markListener, MarkEventType, removeMessageEventType)
+
+	// listen to level events
+	world.AddListener(ts.levelEvents, winning.LevelEndEventType)
+
+	return nil
+}
+
+// fire the marks that the progress has pass
+func (ts *timelineSystem) progressListener(world *goecs.World, signal interface{}, _ float32) error {
+	if ts.end {
+		return nil
+	}
+	switch e := signal.(type) {
+	case winning.ProgressEvent:
+		for _, m := range ts.timeline.Advance(e.Percent) {
+			world.Signal(MarkEvent{Mark: m})
+		}
+	}
+	return nil
+}
+
+// display the messages in the timeline
+func (ts *timelineSystem) markListener(world *goecs.World, signal interface{}, _ float32) error {
+	switch e := signal.(type) {
+	case MarkEvent:
+		if e.Mark.Event != MessageEvent {
+			return nil
+		}
+		ent := world.AddEntity(
+			ui.Text{
+				String:     e.Mark.Value,
+				Size:       fontSize * ts.gs.Max,
+				Font:       font,
+				VAlignment: ui.MiddleVAlignment,
+				HAlignment: ui.CenterHAlignment,
+			},
+			geometry.Point{
+				X: ts.dr.Width * 0.5 * ts.gs.Max,
+				Y: ts.dr.Height * 0.25 * ts.gs.Max,
+			},
+			color.White,
+			effects.Layer{Depth: -100},
+		)
+		world.Signal(events.DelaySignal{
+			Signal: removeMessageEvent{ent: ent},
+			Time:   messageTime,
+		})
+	case removeMessageEvent:
+		_ = world.Remove(e.ent)
+	}
+	return nil
+}
+
+func (ts *timelineSystem) levelEvents(_ *goecs.World, signal interface{}, _ float32) error {
+	switch signal.(type) {
+	case winning.LevelEndEvent:
+		ts.end = true
+	}
+	return nil
+}
+
+// System create the timeline system
+func System(engine *gosge.Engine, gs geometry.Scale, dr geometry.Size, timeline Timeline) error {
+	ts := timelineSystem{
+		gs:       gs,
+		dr:       dr,
+		timeline: timeline,
+	}
+
+	return ts.load(engine)
+}
@@ -0,0 +1,145 @@
+/*
+ * Copyright (c) 2020 Juan Medina.
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package timeline
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+)
+
+// events that could be scripted
+const (
+	SpawnEvent    = "spawn"    // SpawnEvent spawn Count enemies of the kind in Value
+	MessageEvent  = "message"  // MessageEvent display the message in Value
+	FirewallEvent = "firewall" // FirewallEvent sweep a firewall laser through the screen
+	StormEvent    = "storm"    // StormEvent add a packet storm that push the plane up or down, as in Value
+	GateEvent     = "gate"     // GateEvent add a rate-limiter gate
+	WeatherEvent  = "weather"  // WeatherEvent change the weather to the state in Value
+)
+
+// Check returns if a Value is valid for an event
+type Check func(value string) bool
+
+var (
+	// checks for the Value of each event that could be scripted
+	checks = map[string]Check{
+		SpawnEvent:    notEmpty,
+		MessageEvent:  notEmpty,
+		FirewallEvent: anyValue,
+		StormEvent:    oneOf("up", "down"),
+		GateEvent:     anyValue,
+		WeatherEvent:  notEmpty,
+	}
+)
+
+// a Value is required
+func notEmpty(value string) bool {
+	return value != ""
+}
+
+// the Value is not used
+func anyValue(_ string) bool {
+	return true
+}
+
+// the Value need to be one of the given
+func oneOf(values ...string) Check {
+	return func(value string) bool {
+		for _, v := range values {
+			if v == value {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Mark is a scripted event at a point of the level
+type Mark struct {
+	At    float32 `json:"at"`    // At is the progress to production, from 0 to 1
+	Event string  `json:"event"` // Event is the name of the event to fire
+	Value string  `json:"value"` // Value for the event, as the enemy to spawn
+	Count int     `json:"count"` // Count for the event, as the number of enemies to spawn
+}
+
+// Timeline is the scripted events of a level, in progress order
+type Timeline struct {
+	Marks []Mark `json:"timeline"` // Marks in the timeline
+	next  int    // next mark to fire
+}
+
+// Load a Timeline from a level file, the Value of the events are validated with our checks,
+// and the additional ones given by event, as the enemies that could be spawn
+func Load(fileName string, additional map[string]Check) (Timeline, error) {
+	var t Timeline
+
+	data, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return t, err
+	}
+
+	return parse(data, additional)
+}
+
+// parse a Timeline from its json data, and validate it
+func parse(data []byte, additional map[string]Check) (Timeline, error) {
+	var t Timeline
+
+	if err := json.Unmarshal(data, &t); err != nil {
+		return t, err
+	}
+
+	for i, m := range t.Marks {
+		if m.At < 0 || m.At > 1 {
+			return t, fmt.Errorf("mark %d at %v is not between 0 and 1", i+1, m.At)
+		}
+		if m.Event == "" {
+			return t, fmt.Errorf("mark %d at %v has no event", i+1, m.At)
+		}
+		check, ok := checks[m.Event]
+		if !ok {
+			return t, fmt.Errorf("mark %d at %v has an unknown event %q", i+1, m.At, m.Event)
+		}
+		if extra, ok := additional[m.Event]; !check(m.Value) || (ok && !extra(m.Value)) {
+			return t, fmt.Errorf("mark %d at %v has an invalid %s value %q", i+1, m.At, m.Event, m.Value)
+		}
+		if m.Event == SpawnEvent && m.Count <= 0 {
+			return t, fmt.Errorf("mark %d at %v need to spawn at least one enemy", i+1, m.At)
+		}
+	}
+
+	sort.SliceStable(t.Marks, func(i, j int) bool { return t.Marks[i].At < t.Marks[j].At })
+
+	return t, nil
+}
+
+// Advance the Timeline to a progress, returns the marks that has been pass
+func (t *Timeline) Advance(progress float32) []Mark {
+	from := t.next
+	for t.next < len(t.Marks) && t.Marks[t.next].At <= progress {
+		t.next++
+	}
+	return t.Marks[from:t.next]
+}
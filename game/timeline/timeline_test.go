@@ -0,0 +1,119 @@
+/*
+ * Copyright (c) 2020 Juan Medina.
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package timeline
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	for _, env := range []string{"dev", "test", "staging", "prod"} {
+		if _, err := Load(fmt.Sprintf("../../resources/levels/%s.json", env), nil); err != nil {
+			t.Fatalf("load %s error, got %v, expect nil", env, err)
+		}
+	}
+
+	if _, err := Load("not_found.json", nil); err == nil {
+		t.Fatalf("load error, got nil, expect error")
+	}
+}
+
+func TestParse(t *testing.T) {
+	additional := map[string]Check{
+		SpawnEvent: oneOf("Drifter"),
+	}
+
+	type tc struct {
+		mark  string
+		valid bool
+	}
+
+	cases := []tc{
+		{mark: `{"at": 0.1, "event": "spawn", "value": "Drifter", "count": 2}`, valid: true},
+		{mark: `{"at": 0.1, "event": "spawn", "value": "Unknown", "count": 2}`, valid: false},
+		{mark: `{"at": 0.1, "event": "spawn", "value": "Drifter"}`, valid: false},
+		{mark: `{"at": 0.1, "event": "message", "value": "hello"}`, valid: true},
+		{mark: `{"at": 0.1, "event": "message"}`, valid: false},
+		{mark: `{"at": 0.1, "event": "storm", "value": "up"}`, valid: true},
+		{mark: `{"at": 0.1, "event": "storm", "value": "down"}`, valid: true},
+		{mark: `{"at": 0.1, "event": "storm", "value": "left"}`, valid: false},
+		{mark: `{"at": 0.1, "event": "firewall"}`, valid: true},
+		{mark: `{"at": 0.1, "event": "gate"}`, valid: true},
+		{mark: `{"at": 0.1, "event": "weather", "value": "fog"}`, valid: true},
+		{mark: `{"at": 0.1, "event": "weather"}`, valid: false},
+		{mark: `{"at": 0.1, "event": "firewal"}`, valid: false},
+		{mark: `{"at": 0.1}`, valid: false},
+		{mark: `{"at": 1.1, "event": "gate"}`, valid: false},
+	}
+
+	for i, v := range cases {
+		t.Run(fmt.Sprintf("case %d", i+1), func(t *testing.T) {
+			data := []byte(fmt.Sprintf(`{"timeline": [%s]}`, v.mark))
+			if _, err := parse(data, additional); (err == nil) != v.valid {
+				t.Fatalf("parse error, got %v, expect valid %v", err, v.valid)
+			}
+		})
+	}
+}
+
+func TestTimeline_Advance(t *testing.T) {
+	tl := Timeline{
+		Marks: []Mark{
+			{At: 0.1, Event: "first"},
+			{At: 0.3, Event: "second"},
+			{At: 0.3, Event: "third"},
+			{At: 0.9, Event: "fourth"},
+		},
+	}
+
+	type tc struct {
+		progress float32
+		expect   []string
+	}
+
+	cases := []tc{
+		{progress: 0, expect: []string{}},
+		{progress: 0.1, expect: []string{"first"}},
+		{progress: 0.2, expect: []string{}},
+		{progress: 0.5, expect: []string{"second", "third"}},
+		// progress does not go back
+		{progress: 0.1, expect: []string{}},
+		{progress: 1, expect: []string{"fourth"}},
+		{progress: 1, expect: []string{}},
+	}
+
+	for i, v := range cases {
+		t.Run(fmt.Sprintf("case %d", i+1), func(t *testing.T) {
+			got := tl.Advance(v.progress)
+			if len(got) != len(v.expect) {
+				t.Fatalf("advance error, got %v, expect %v", got, v.expect)
+			}
+			for j, m := range got {
+				if m.Event != v.expect[j] {
+					t.Fatalf("advance error, got %v, expect %v", got, v.expect)
+				}
+			}
+		})
+	}
+}
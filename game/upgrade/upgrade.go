@@ -0,0 +1,125 @@
+/*
+ * Copyright (c) 2020 Juan Medina.
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package upgrade
+
+import (
+	"fmt"
+	"github.com/juan-medina/gosge/options"
+	"github.com/juan-medina/mesh2prod/game/constants"
+)
+
+// Upgrade is a permanent upgrade that could be bought with BlockCoins
+type Upgrade int
+
+// upgrades
+const (
+	PlaneSpeed    = Upgrade(iota) // PlaneSpeed increase the plane and mesh speed
+	FireRate                      // FireRate increase the bullet speed and the rapid fire rate
+	MeshArmour                    // MeshArmour increase the blocks that the mesh could hit
+	StartPowerUps                 // StartPowerUps start the level with power-ups active
+)
+
+// info for an Upgrade
+type info struct {
+	name    string  // name to display
+	setting string  // setting name
+	levels  int     // max level
+	cost    int     // cost of the first level, each level cost more
+	base    float32 // value without the upgrade
+	step    float32 // value increase per level
+}
+
+var (
+	// Upgrades is our upgrades in display order
+	Upgrades = []Upgrade{PlaneSpeed, FireRate, MeshArmour, StartPowerUps}
+
+	upgrades = map[Upgrade]info{
+		PlaneSpeed:    {name: "Plane Speed", setting: "plane_speed", levels: 5, cost: 100, base: 1, step: 0.1},
+		FireRate:      {name: "Fire Rate", setting: "fire_rate", levels: 5, cost: 100, base: 1, step: 0.15},
+		MeshArmour:    {name: "Mesh Armour", setting: "mesh_armour", levels: 5, cost: 150, base: 0, step: 5},
+		StartPowerUps: {name: "Start Power-ups", setting: "start_power_ups", levels: 3, cost: 250, base: 0, step: 1},
+	}
+)
+
+// Name returns the display name of the Upgrade
+func (u Upgrade) Name() string {
+	return upgrades[u].name
+}
+
+// Levels returns the max level of the Upgrade
+func (u Upgrade) Levels() int {
+	return upgrades[u].levels
+}
+
+// Level returns the current level of the Upgrade
+func (u Upgrade) Level(settings options.Settings) int {
+	return int(settings.GetIn32(u.config(), 0))
+}
+
+// Value returns the Upgrade value for the current level, a factor for PlaneSpeed and FireRate,
+// the extra blocks for MeshArmour and the number of power-ups for StartPowerUps
+func (u Upgrade) Value(settings options.Settings) float32 {
+	i := upgrades[u]
+	return i.base + (i.step * float32(u.Level(settings)))
+}
+
+// Cost returns the cost of the next level of the Upgrade, and if there is a next level
+func (u Upgrade) Cost(settings options.Settings) (int, bool) {
+	lvl := u.Level(settings)
+	if lvl >= u.Levels() {
+		return 0, false
+	}
+	return upgrades[u].cost * (lvl + 1), true
+}
+
+// Buy the next level of the Upgrade, returns false if is at max level or the wallet does not have enough coins
+func (u Upgrade) Buy(settings options.Settings) bool {
+	cost, ok := u.Cost(settings)
+	if !ok {
+		return false
+	}
+	coins := Wallet(settings)
+	if coins < cost {
+		return false
+	}
+	settings.SetInt32(constants.WalletConfig, int32(coins-cost))
+	settings.SetInt32(u.config(), int32(u.Level(settings)+1))
+	return true
+}
+
+// config returns the setting name for the Upgrade level
+func (u Upgrade) config() string {
+	return fmt.Sprintf(constants.UpgradeConfig, upgrades[u].setting)
+}
+
+// Wallet returns the BlockCoins in the wallet
+func Wallet(settings options.Settings) int {
+	return int(settings.GetIn32(constants.WalletConfig, 0))
+}
+
+// Deposit BlockCoins in the wallet, only positive amounts are added
+func Deposit(settings options.Settings, coins int) {
+	if coins > 0 {
+		settings.SetInt32(constants.WalletConfig, int32(Wallet(settings)+coins))
+	}
+}
@@ -0,0 +1,117 @@
+/*
+ * Copyright (c) 2020 Juan Medina.
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package upgrade
+
+import (
+	"fmt"
+	"github.com/juan-medina/mesh2prod/game/constants"
+	"testing"
+)
+
+// settings that are only keep in memory
+type memSettings map[string]interface{}
+
+func (ms memSettings) Get(setting string, _default interface{}) interface{} {
+	if v, ok := ms[setting]; ok {
+		return v
+	}
+	return _default
+}
+
+func (ms memSettings) Set(setting string, value interface{}) {
+	ms[setting] = value
+}
+
+func (ms memSettings) GetFloat32(setting string, _default float32) float32 {
+	return ms.Get(setting, _default).(float32)
+}
+
+func (ms memSettings) SetFloat32(setting string, value float32) {
+	ms.Set(setting, value)
+}
+
+func (ms memSettings) GetString(setting string, _default string) string {
+	return ms.Get(setting, _default).(string)
+}
+
+func (ms memSettings) SetString(setting string, value string) {
+	ms.Set(setting, value)
+}
+
+func (ms memSettings) GetIn32(setting string, _default int32) int32 {
+	return ms.Get(setting, _default).(int32)
+}
+
+func (ms memSettings) SetInt32(setting string, value int32) {
+	ms.Set(setting, value)
+}
+
+func TestUpgrade_Buy(t *testing.T) {
+	type tc struct {
+		wallet       int
+		level        int
+		expect       bool
+		expectWallet int
+		expectLevel  int
+	}
+
+	cases := []tc{
+		{wallet: 0, level: 0, expect: false, expectWallet: 0, expectLevel: 0},
+		{wallet: 100, level: 0, expect: true, expectWallet: 0, expectLevel: 1},
+		{wallet: 150, level: 1, expect: false, expectWallet: 150, expectLevel: 1},
+		{wallet: 250, level: 1, expect: true, expectWallet: 50, expectLevel: 2},
+		{wallet: 5000, level: 5, expect: false, expectWallet: 5000, expectLevel: 5},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("case %d", i+1), func(t *testing.T) {
+			settings := memSettings{}
+			settings.SetInt32(constants.WalletConfig, int32(c.wallet))
+			settings.SetInt32(PlaneSpeed.config(), int32(c.level))
+
+			if got := PlaneSpeed.Buy(settings); got != c.expect {
+				t.Fatalf("buy error, got %v, expect %v", got, c.expect)
+			}
+
+			if got := Wallet(settings); got != c.expectWallet {
+				t.Fatalf("wallet error, got %v, expect %v", got, c.expectWallet)
+			}
+
+			if got := PlaneSpeed.Level(settings); got != c.expectLevel {
+				t.Fatalf("level error, got %v, expect %v", got, c.expectLevel)
+			}
+		})
+	}
+}
+
+func TestDeposit(t *testing.T) {
+	settings := memSettings{}
+
+	Deposit(settings, 120)
+	Deposit(settings, -50)
+	Deposit(settings, 30)
+
+	if got := Wallet(settings); got != 150 {
+		t.Fatalf("deposit error, got %v, expect %v", got, 150)
+	}
+}
@@ -0,0 +1,136 @@
+/*
+ * Copyright (c) 2020 Juan Medina.
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package wave
+
+import (
+	"github.com/juan-medina/goecs"
+	"github.com/juan-medina/gosge"
+	"github.com/juan-medina/gosge/components/geometry"
+	"github.com/juan-medina/mesh2prod/game/enemy"
+	"github.com/juan-medina/mesh2prod/game/pause"
+	"github.com/juan-medina/mesh2prod/game/winning"
+)
+
+// logic constants
+const (
+	formationGap = 80  // gap between the enemies in a formation
+	spawnGap     = 100 // gap to the right of the screen where the waves spawn
+)
+
+type waveSystem struct {
+	pause.State
+	gs       geometry.Scale
+	dr       geometry.Size
+	waves    []Wave  // waves to spawn
+	spawned  []bool  // waves that has been spawn
+	time     float32 // time since the level start
+	progress float32 // progress to production
+	end      bool
+	boss     bool // is the boss stage on
+}
+
+// load the system
+func (ws *waveSystem) load(eng *gosge.Engine) error {
+	world := eng.World()
+
+	// spawn the waves
+	world.AddSystem(ws.spawnSystem)
+
+	// listen to the progress
+	world.AddListener(ws.progressListener, winning.ProgressEventType)
+
+	// keep track of the pause
+	ws.Track(world)
+
+	// listen to level events
+	world.AddListener(ws.levelEvents, winning.LevelEndEventType,
+		winning.BossStageEventType, winning.BossDefeatedEventType)
+
+	return nil
+}
+
+// spawn the waves that are due
+func (ws *waveSystem) spawnSystem(world *goecs.World, delta float32) error {
+	// no waves spawn during the boss stage
+	if ws.end || ws.IsPaused() || ws.boss {
+		return nil
+	}
+	ws.time += delta
+	for i, w := range ws.waves {
+		if !ws.spawned[i] && w.Due(ws.time, ws.progress) {
+			ws.spawned[i] = true
+			ws.spawn(world, w)
+		}
+	}
+	return nil
+}
+
+// spawn a wave in its formation, off the screen on the right
+func (ws waveSystem) spawn(world *goecs.World, w Wave) {
+	center := geometry.Point{
+		X: ws.dr.Width + spawnGap,
+		Y: ws.dr.Height * w.Y,
+	}
+	for _, offset := range w.Formation.Offsets(w.Count, formationGap) {
+		world.Signal(enemy.SpawnEvent{
+			Kind: w.Kind,
+			At: geometry.Point{
+				X: (center.X + offset.X) * ws.gs.Max,
+				Y: (center.Y + offset.Y) * ws.gs.Max,
+			},
+		})
+	}
+}
+
+// keep track of the progress to production
+func (ws *waveSystem) progressListener(_ *goecs.World, signal interface{}, _ float32) error {
+	switch e := signal.(type) {
+	case winning.ProgressEvent:
+		ws.progress = e.Percent
+	}
+	return nil
+}
+
+func (ws *waveSystem) levelEvents(_ *goecs.World, signal interface{}, _ float32) error {
+	switch signal.(type) {
+	case winning.LevelEndEvent:
+		ws.end = true
+	case winning.BossStageEvent:
+		ws.boss = true
+	case winning.BossDefeatedEvent:
+		ws.boss = false
+	}
+	return nil
+}
+
+// System create the wave system
+func System(engine *gosge.Engine, gs geometry.Scale, dr geometry.Size, waves []Wave) error {
+	ws := waveSystem{
+		gs:      gs,
+		dr:      dr,
+		waves:   waves,
+		spawned: make([]bool, len(waves)),
+	}
+
+	return ws.load(engine)
+}
@@ -0,0 +1,294 @@
+/*
+ * Copyright (c) 2020 Juan Medina.
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package wave
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/juan-medina/gosge/components/geometry"
+	"github.com/juan-medina/mesh2prod/game/enemy"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Formation is how the enemies in a Wave are placed
+type Formation string
+
+// formations
+const (
+	Line   = Formation("line")   // Line one after another
+	Column = Formation("column") // Column one on top of another
+	Vee    = Formation("vee")    // Vee in a V shape, pointing to the plane
+)
+
+// Offsets returns the position of each enemy in the Formation, relative to its center, for a gap between them
+func (f Formation) Offsets(count int, gap float32) []geometry.Point {
+	result := make([]geometry.Point, count)
+	mid := float32(count-1) * 0.5
+	for i := range result {
+		pos := float32(i) - mid
+		switch f {
+		case Line:
+			result[i] = geometry.Point{X: float32(i) * gap}
+		case Column:
+			result[i] = geometry.Point{Y: pos * gap}
+		case Vee:
+			if pos < 0 {
+				result[i] = geometry.Point{X: -pos * gap, Y: pos * gap}
+			} else {
+				result[i] = geometry.Point{X: pos * gap, Y: pos * gap}
+			}
+		}
+	}
+	return result
+}
+
+// Wave is an attack wave
+type Wave struct {
+	Line      int        // Line in the file where the wave is defined
+	Progress  float32    // Progress to production when the wave spawn, from 0 to 1
+	Time      float32    // Time, in seconds since the level start, when the wave spawn
+	ByTime    bool       // ByTime indicates that the wave spawn by Time instead of by Progress
+	Kind      enemy.Kind // Kind of enemy, with the wave path and drops
+	Count     int        // Count is the number of enemies
+	Formation Formation  // Formation of the enemies
+	Y         float32    // Y is the screen height factor of the formation center
+}
+
+// Due returns if the Wave should spawn, for a time since the level start and a progress to production
+func (w Wave) Due(time, progress float32) bool {
+	if w.ByTime {
+		return time >= w.Time
+	}
+	return progress >= w.Progress
+}
+
+// Error is an error in a waves file, at a line
+type Error struct {
+	File string // File with the error
+	Line int    // Line of the error
+	Msg  string // Msg describing the error
+}
+
+// Error returns the error message
+func (e Error) Error() string {
+	return fmt.Sprintf("%s:%d: %s", e.File, e.Line, e.Msg)
+}
+
+// Load the waves from a file
+func Load(fileName string, catalogue enemy.Catalogue) ([]Wave, error) {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+
+	return Parse(fileName, file, catalogue)
+}
+
+// Parse the waves from a reader, the enemies should exist in the catalogue.
+//
+// Each wave starts with 'wave at <progress>%' or 'wave after <seconds>s' followed by its properties:
+//
+//	enemy <name>                          the enemy kind, required
+//	count <number>                        the number of enemies, default 1
+//	formation line|column|vee             how the enemies are placed, default line
+//	y <percent>%                          the screen height of the formation center, default 50%
+//	path sine|dive|hover                  how the enemies fly, default the enemy pattern
+//	drop coins <number> pickup <chance>%  the enemies drops, default the enemy drops
+//
+// Empty lines, and lines starting with #, are ignored.
+func Parse(name string, r io.Reader, catalogue enemy.Catalogue) ([]Wave, error) {
+	waves := make([]Wave, 0)
+	var current *Wave
+
+	// the path and drops, that override the enemy ones
+	var path enemy.Pattern
+	var coins *int
+	var pickup *float32
+
+	fail := func(line int, format string, a ...interface{}) error {
+		return Error{File: name, Line: line, Msg: fmt.Sprintf(format, a...)}
+	}
+
+	// check that the current wave is complete, and add it
+	closeWave := func() error {
+		if current == nil {
+			return nil
+		}
+		if current.Kind.Name == "" {
+			return fail(current.Line, "wave has no enemy")
+		}
+		if path != "" {
+			current.Kind.Pattern = path
+		}
+		if coins != nil {
+			current.Kind.Coins = *coins
+		}
+		if pickup != nil {
+			current.Kind.Pickup = *pickup
+		}
+		waves = append(waves, *current)
+		current, path, coins, pickup = nil, "", nil, nil
+		return nil
+	}
+
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		fields := strings.Fields(text)
+		args := fields[1:]
+
+		if fields[0] == "wave" {
+			if err := closeWave(); err != nil {
+				return nil, err
+			}
+			current = &Wave{Line: line, Count: 1, Formation: Line, Y: 0.5}
+			if len(args) != 2 {
+				return nil, fail(line, "wave need 'at <progress>%%' or 'after <seconds>s'")
+			}
+			switch args[0] {
+			case "at":
+				value, err := percent(args[1])
+				if err != nil {
+					return nil, fail(line, "invalid progress %q, %v", args[1], err)
+				}
+				current.Progress = value
+			case "after":
+				value, err := strconv.ParseFloat(strings.TrimSuffix(args[1], "s"), 32)
+				if err != nil || value < 0 || !strings.HasSuffix(args[1], "s") {
+					return nil, fail(line, "invalid time %q, expect seconds like 10s", args[1])
+				}
+				current.Time = float32(value)
+				current.ByTime = true
+			default:
+				return nil, fail(line, "wave need 'at <progress>%%' or 'after <seconds>s'")
+			}
+			continue
+		}
+
+		if current == nil {
+			return nil, fail(line, "%q is outside a wave", fields[0])
+		}
+
+		switch fields[0] {
+		case "enemy":
+			if len(args) != 1 {
+				return nil, fail(line, "enemy need a name")
+			}
+			kind, ok := catalogue.Find(args[0])
+			if !ok {
+				return nil, fail(line, "unknown enemy %q", args[0])
+			}
+			current.Kind = catalogue.Enemies[kind]
+		case "count":
+			value, err := strconv.Atoi(strings.Join(args, " "))
+			if err != nil || value < 1 {
+				return nil, fail(line, "invalid count %q, expect a number greater than 0", strings.Join(args, " "))
+			}
+			current.Count = value
+		case "formation":
+			if len(args) != 1 {
+				return nil, fail(line, "formation need line, column or vee")
+			}
+			switch f := Formation(args[0]); f {
+			case Line, Column, Vee:
+				current.Formation = f
+			default:
+				return nil, fail(line, "unknown formation %q, expect line, column or vee", args[0])
+			}
+		case "y":
+			if len(args) != 1 {
+				return nil, fail(line, "y need a percent")
+			}
+			value, err := percent(args[0])
+			if err != nil {
+				return nil, fail(line, "invalid y %q, %v", args[0], err)
+			}
+			current.Y = value
+		case "path":
+			if len(args) != 1 {
+				return nil, fail(line, "path need sine, dive or hover")
+			}
+			switch p := enemy.Pattern(args[0]); p {
+			case enemy.Sine, enemy.Dive, enemy.Hover:
+				path = p
+			default:
+				return nil, fail(line, "unknown path %q, expect sine, dive or hover", args[0])
+			}
+		case "drop":
+			if len(args) == 0 || len(args)%2 != 0 {
+				return nil, fail(line, "drop need 'coins <number>' and/or 'pickup <chance>%%'")
+			}
+			for i := 0; i < len(args); i += 2 {
+				switch args[i] {
+				case "coins":
+					value, err := strconv.Atoi(args[i+1])
+					if err != nil || value < 0 {
+						return nil, fail(line, "invalid coins %q, expect a number", args[i+1])
+					}
+					coins = &value
+				case "pickup":
+					value, err := percent(args[i+1])
+					if err != nil {
+						return nil, fail(line, "invalid pickup %q, %v", args[i+1], err)
+					}
+					pickup = &value
+				default:
+					return nil, fail(line, "unknown drop %q, expect coins or pickup", args[i])
+				}
+			}
+		default:
+			return nil, fail(line, "unknown property %q", fields[0])
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := closeWave(); err != nil {
+		return nil, err
+	}
+
+	return waves, nil
+}
+
+// parse a percent, like 30%, to a factor from 0 to 1
+func percent(text string) (float32, error) {
+	if !strings.HasSuffix(text, "%") {
+		return 0, fmt.Errorf("expect a percent like 30%%")
+	}
+	value, err := strconv.ParseFloat(strings.TrimSuffix(text, "%"), 32)
+	if err != nil || value < 0 || value > 100 {
+		return 0, fmt.Errorf("expect a percent from 0%% to 100%%")
+	}
+	return float32(value / 100), nil
+}
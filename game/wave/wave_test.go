@@ -0,0 +1,162 @@
+/*
+ * Copyright (c) 2020 Juan Medina.
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package wave
+
+import (
+	"fmt"
+	"github.com/juan-medina/gosge/components/geometry"
+	"github.com/juan-medina/mesh2prod/game/enemy"
+	"github.com/juan-medina/mesh2prod/game/skin"
+	"strings"
+	"testing"
+)
+
+var testCatalogue = enemy.Catalogue{
+	Enemies: []enemy.Kind{
+		{Skin: skin.Skin{Name: "Drifter"}, Pattern: enemy.Sine, Coins: 3, Pickup: 0.1},
+		{Skin: skin.Skin{Name: "Diver"}, Pattern: enemy.Dive, Coins: 1},
+	},
+}
+
+func TestLoad(t *testing.T) {
+	catalogue, err := enemy.Load("../../resources/enemies/enemies.json")
+	if err != nil {
+		t.Fatalf("load catalogue error, got %v, expect nil", err)
+	}
+
+	for _, env := range []string{"dev", "test", "staging", "prod"} {
+		if _, err = Load(fmt.Sprintf("../../resources/waves/%s.waves", env), catalogue); err != nil {
+			t.Fatalf("load %s error, got %v, expect nil", env, err)
+		}
+	}
+
+	if _, err = Load("not_found.waves", catalogue); err == nil {
+		t.Fatalf("load error, got nil, expect error")
+	}
+}
+
+func TestParse(t *testing.T) {
+	text := `# a comment
+wave at 30%
+  enemy Drifter
+  count 3
+  formation vee
+  path dive
+  drop pickup 50%
+
+wave after 10s
+  drop coins 7
+  enemy Diver
+  y 25%
+`
+	waves, err := Parse("test", strings.NewReader(text), testCatalogue)
+	if err != nil {
+		t.Fatalf("parse error, got %v, expect nil", err)
+	}
+
+	if len(waves) != 2 {
+		t.Fatalf("waves error, got %v, expect %v", len(waves), 2)
+	}
+
+	first := waves[0]
+	if first.Line != 2 || first.ByTime || first.Progress != 0.3 || first.Count != 3 || first.Formation != Vee {
+		t.Fatalf("first wave error, got %+v", first)
+	}
+	if first.Kind.Pattern != enemy.Dive || first.Kind.Coins != 3 || first.Kind.Pickup != 0.5 {
+		t.Fatalf("first wave kind error, got %+v", first.Kind)
+	}
+
+	second := waves[1]
+	if second.Line != 9 || !second.ByTime || second.Time != 10 || second.Count != 1 || second.Formation != Line {
+		t.Fatalf("second wave error, got %+v", second)
+	}
+	if second.Kind.Pattern != enemy.Dive || second.Kind.Coins != 7 || second.Y != 0.25 {
+		t.Fatalf("second wave kind error, got %+v", second)
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	type tc struct {
+		text string
+		line int
+	}
+
+	cases := []tc{
+		{text: "enemy Drifter", line: 1},
+		{text: "wave at 30%\n  enemy Unknown", line: 2},
+		{text: "wave at 130%\n  enemy Drifter", line: 1},
+		{text: "wave at 30\n  enemy Drifter", line: 1},
+		{text: "wave after ten\n  enemy Drifter", line: 1},
+		{text: "wave\n  enemy Drifter", line: 1},
+		{text: "\n\nwave at 30%\n  count 3\n", line: 3},
+		{text: "wave at 30%\n  enemy Drifter\n  count 0", line: 3},
+		{text: "wave at 30%\n  enemy Drifter\n  formation circle", line: 3},
+		{text: "wave at 30%\n  enemy Drifter\n  path loop", line: 3},
+		{text: "wave at 30%\n  enemy Drifter\n  drop gold 3", line: 3},
+		{text: "wave at 30%\n  enemy Drifter\n  drop coins", line: 3},
+		{text: "wave at 30%\n  enemy Drifter\n  speed 3", line: 3},
+		{text: "wave at 30%\n  enemy Drifter\nwave at 50%\n  count 2", line: 3},
+	}
+
+	for i, v := range cases {
+		t.Run(fmt.Sprintf("case %d", i+1), func(t *testing.T) {
+			_, err := Parse("test", strings.NewReader(v.text), testCatalogue)
+			got, ok := err.(Error)
+			if !ok {
+				t.Fatalf("parse error, got %v, expect wave.Error", err)
+			}
+			if got.Line != v.line {
+				t.Fatalf("line error, got %v, expect %v", got.Line, v.line)
+			}
+		})
+	}
+}
+
+func TestFormation_Offsets(t *testing.T) {
+	type tc struct {
+		formation Formation
+		count     int
+		expect    []geometry.Point
+	}
+
+	cases := []tc{
+		{formation: Line, count: 3, expect: []geometry.Point{{X: 0}, {X: 10}, {X: 20}}},
+		{formation: Column, count: 3, expect: []geometry.Point{{Y: -10}, {Y: 0}, {Y: 10}}},
+		{formation: Vee, count: 3, expect: []geometry.Point{{X: 10, Y: -10}, {X: 0, Y: 0}, {X: 10, Y: 10}}},
+		{formation: Column, count: 1, expect: []geometry.Point{{}}},
+	}
+
+	for i, v := range cases {
+		t.Run(fmt.Sprintf("case %d", i+1), func(t *testing.T) {
+			got := v.formation.Offsets(v.count, 10)
+			if len(got) != len(v.expect) {
+				t.Fatalf("offsets error, got %v, expect %v", got, v.expect)
+			}
+			for j := range got {
+				if got[j] != v.expect[j] {
+					t.Fatalf("offsets error, got %v, expect %v", got, v.expect)
+				}
+			}
+		})
+	}
+}
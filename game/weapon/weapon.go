@@ -0,0 +1,180 @@
+/*
+ * Copyright (c) 2020 Juan Medina.
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package weapon
+
+import (
+	"github.com/juan-medina/gosge/components/color"
+)
+
+// Effect is what a projectile does when it hits a block
+type Effect int
+
+// effects
+const (
+	PlaceBlock   = Effect(iota) // PlaceBlock place a block in front of the block hit
+	DestroyBlock                // DestroyBlock remove the block hit
+	PaintBlock                  // PaintBlock turn the block hit into a placed block
+)
+
+// Unlimited is the ammo for weapons that never run out
+const Unlimited = -1
+
+// Shot is a projectile in a weapon pattern
+type Shot struct {
+	Row   int     // Row offset from the target row, in blocks
+	Delay float32 // Delay in seconds from the trigger
+}
+
+// Weapon fire projectiles toward the target
+type Weapon interface {
+	Name() string       // Name to display
+	Color() color.Solid // Color of the projectiles
+	Delay() float32     // Delay in seconds between shots
+	Heat() float32      // Heat added by each shot, we overheat at 1
+	Cooling() float32   // Cooling is the heat that we lose per second
+	Pattern() []Shot    // Pattern of projectiles on each shot
+	Effect() Effect     // Effect of the projectiles
+	Piercing() bool     // Piercing projectiles continue after hitting a block
+	Ammo() int          // Ammo at the level start, or Unlimited
+	Gravity() float32   // Gravity that pull the projectiles down, 0 for a straight shot
+	Bounce() float32    // Bounce is the velocity that the projectiles keep when they ricochet
+}
+
+// weapon is a Weapon defined by its values
+type weapon struct {
+	name     string
+	color    color.Solid
+	delay    float32
+	heat     float32
+	cooling  float32
+	pattern  []Shot
+	effect   Effect
+	piercing bool
+	ammo     int
+	gravity  float32
+	bounce   float32
+}
+
+func (w weapon) Name() string {
+	return w.name
+}
+
+func (w weapon) Color() color.Solid {
+	return w.color
+}
+
+func (w weapon) Delay() float32 {
+	return w.delay
+}
+
+func (w weapon) Heat() float32 {
+	return w.heat
+}
+
+func (w weapon) Cooling() float32 {
+	return w.cooling
+}
+
+func (w weapon) Pattern() []Shot {
+	return w.pattern
+}
+
+func (w weapon) Effect() Effect {
+	return w.effect
+}
+
+func (w weapon) Piercing() bool {
+	return w.piercing
+}
+
+func (w weapon) Ammo() int {
+	return w.ammo
+}
+
+func (w weapon) Gravity() float32 {
+	return w.gravity
+}
+
+func (w weapon) Bounce() float32 {
+	return w.bounce
+}
+
+// our weapons
+var (
+	// Single fires one projectile that place a block
+	Single Weapon = weapon{
+		name:    "Single",
+		color:   color.Red.Alpha(180),
+		delay:   0.15,
+		heat:    0.08,
+		cooling: 0.5,
+		pattern: []Shot{{}},
+		effect:  PlaceBlock,
+		ammo:    Unlimited,
+		bounce:  0.8,
+	}
+
+	// TwinRows fires two projectiles, to the target row and the one below, that place blocks
+	TwinRows Weapon = weapon{
+		name:    "Twin Rows",
+		color:   color.Orange.Alpha(180),
+		delay:   0.25,
+		heat:    0.15,
+		cooling: 0.5,
+		pattern: []Shot{{}, {Row: 1}},
+		effect:  PlaceBlock,
+		ammo:    40,
+		bounce:  0.8,
+	}
+
+	// Burst fires three arcing projectiles in a quick succession that destroy blocks
+	Burst Weapon = weapon{
+		name:    "Burst",
+		color:   color.Purple.Alpha(180),
+		delay:   0.6,
+		heat:    0.3,
+		cooling: 0.6,
+		pattern: []Shot{{}, {Delay: 0.08}, {Delay: 0.16}},
+		effect:  DestroyBlock,
+		ammo:    30,
+		gravity: 900,
+		bounce:  0.6,
+	}
+
+	// SidecarInjector fires a projectile that paint every block in its way
+	SidecarInjector Weapon = weapon{
+		name:     "Sidecar Injector",
+		color:    color.SkyBlue.Alpha(200),
+		delay:    0.8,
+		heat:     0.45,
+		cooling:  0.4,
+		pattern:  []Shot{{}},
+		effect:   PaintBlock,
+		piercing: true,
+		ammo:     15,
+		bounce:   0.8,
+	}
+
+	// Weapons is our weapons in the order that they are cycled
+	Weapons = []Weapon{Single, TwinRows, Burst, SidecarInjector}
+)
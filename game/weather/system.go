@@ -0,0 +1,211 @@
+/*
+ * Copyright (c) 2020 Juan Medina.
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package weather
+
+import (
+	"github.com/juan-medina/goecs"
+	"github.com/juan-medina/gosge"
+	"github.com/juan-medina/gosge/components/color"
+	"github.com/juan-medina/gosge/components/effects"
+	"github.com/juan-medina/gosge/components/geometry"
+	"github.com/juan-medina/gosge/components/shapes"
+	"github.com/juan-medina/mesh2prod/game/constants"
+	"github.com/juan-medina/mesh2prod/game/hazard"
+	"github.com/juan-medina/mesh2prod/game/movement"
+	"github.com/juan-medina/mesh2prod/game/pause"
+	"github.com/juan-medina/mesh2prod/game/timeline"
+	"github.com/juan-medina/mesh2prod/game/winning"
+	"math/rand"
+)
+
+// logic constants
+const (
+	changeSpeed = 0.25 // how much the weather change each second, so 4 seconds for a full change
+	fogFade     = 250  // width of the fog fade
+	fogDepth    = -1.5 // fog layer, over the blocks and their texts
+	darkDepth   = 0.9  // storm darkness layer, over the clouds and under the blocks
+	stormDark   = 120  // storm darkness alpha, at full strength
+	boltFrom    = 0.1  // lightning first X position, as screen width factor
+	boltRange   = 0.6  // lightning X positions range, as screen width factor
+)
+
+var (
+	fogColor  = color.Solid{R: 215, G: 220, B: 225, A: 245} // fog color
+	darkColor = color.Solid{R: 10, G: 10, B: 40, A: 0}      // storm darkness color, alpha is set by the storm
+)
+
+type weatherSystem struct {
+	pause.State
+	gs      geometry.Scale
+	dr      geometry.Size
+	current Conditions    // current weather conditions
+	target  Conditions    // conditions that we are changing to
+	forced  bool          // the weather is forced in the settings
+	time    float32       // weather time, for the turbulence
+	since   float32       // time since the last lightning
+	drifted bool          // we have signal some drift
+	fade    *goecs.Entity // fog fade
+	fog     *goecs.Entity // fog
+	dark    *goecs.Entity // storm darkness
+	end     bool
+}
+
+// load the system
+func (ws *weatherSystem) load(eng *gosge.Engine) error {
+	// a weather state could be forced for testing
+	name := eng.GetSettings().GetString(constants.WeatherConfig, "")
+	if state, ok := Parse(name); ok {
+		ws.forced = true
+		ws.target = For(state)
+		ws.current = ws.target
+	}
+
+	world := eng.World()
+
+	size := geometry.Size{Width: fogFade, Height: ws.dr.Height}
+	ws.fade = world.AddEntity(
+		shapes.SolidBox{
+			Size:  size,
+			Scale: ws.gs.Max,
+		},
+		geometry.Point{},
+		color.Gradient{
+			From:      fogColor.Alpha(0),
+			To:        fogColor,
+			Direction: color.GradientHorizontal,
+		},
+		effects.Layer{Depth: fogDepth},
+	)
+
+	size = geometry.Size{Width: ws.dr.Width, Height: ws.dr.Height}
+	ws.fog = world.AddEntity(
+		shapes.SolidBox{
+			Size:  size,
+			Scale: ws.gs.Max,
+		},
+		geometry.Point{},
+		fogColor,
+		effects.Layer{Depth: fogDepth},
+	)
+
+	ws.dark = world.AddEntity(
+		shapes.SolidBox{
+			Size:  size,
+			Scale: ws.gs.Max,
+		},
+		geometry.Point{},
+		darkColor,
+		effects.Layer{Depth: darkDepth},
+	)
+
+	ws.update()
+
+	// listen to the timeline
+	world.AddListener(ws.timelineListener, timeline.MarkEventType)
+
+	// change the weather
+	world.AddSystem(ws.weatherSystem)
+
+	// keep track of the pause
+	ws.Track(world)
+
+	// listen to level events
+	world.AddListener(ws.levelEvents, winning.LevelEndEventType)
+
+	return nil
+}
+
+// change the weather when the timeline ask to
+func (ws *weatherSystem) timelineListener(_ *goecs.World, signal interface{}, _ float32) error {
+	if ws.forced {
+		return nil
+	}
+	switch e := signal.(type) {
+	case timeline.MarkEvent:
+		if e.Mark.Event == timeline.WeatherEvent {
+			if state, ok := Parse(e.Mark.Value); ok {
+				ws.target = For(state)
+			}
+		}
+	}
+	return nil
+}
+
+// change the weather towards the target, and apply its effects
+func (ws *weatherSystem) weatherSystem(world *goecs.World, delta float32) error {
+	if ws.IsPaused() {
+		return nil
+	}
+
+	ws.current = ws.current.Towards(ws.target, changeSpeed*delta)
+	ws.time += delta
+	ws.since += delta
+
+	ws.update()
+
+	if ws.end {
+		return nil
+	}
+
+	// drift the plane and the bullets
+	if ws.current.Turbulence > 0 || ws.drifted {
+		world.Signal(movement.TurbulenceEvent{Drift: ws.current.Drift(ws.time)})
+		ws.drifted = ws.current.Turbulence > 0
+	}
+
+	// strike a lightning
+	if ws.current.Lightning(ws.since) {
+		ws.since = 0
+		x := ws.dr.Width * (boltFrom + rand.Float32()*boltRange)
+		world.Signal(hazard.LightningEvent{X: x})
+	}
+
+	return nil
+}
+
+// update the fog and the storm darkness to the current conditions
+func (ws *weatherSystem) update() {
+	x := ws.dr.Width * ws.current.Visibility() * ws.gs.Max
+	ws.fade.Set(geometry.Point{X: x})
+	ws.fog.Set(geometry.Point{X: x + fogFade*ws.gs.Max})
+	ws.dark.Set(darkColor.Alpha(uint8(stormDark * ws.current.Storm)))
+}
+
+func (ws *weatherSystem) levelEvents(world *goecs.World, signal interface{}, _ float32) error {
+	switch signal.(type) {
+	case winning.LevelEndEvent:
+		ws.end = true
+		world.Signal(movement.TurbulenceEvent{})
+	}
+	return nil
+}
+
+// System create the weather system
+func System(engine *gosge.Engine, gs geometry.Scale, dr geometry.Size) error {
+	ws := weatherSystem{
+		gs: gs,
+		dr: dr,
+	}
+
+	return ws.load(engine)
+}
@@ -0,0 +1,130 @@
+/*
+ * Copyright (c) 2020 Juan Medina.
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package weather
+
+import (
+	"github.com/juan-medina/gosge/components/geometry"
+	"math"
+)
+
+// State is a weather state
+type State int
+
+// weather states
+const (
+	Clear      = State(iota) // Clear skies, nothing happens
+	Fog                      // Fog limit how far ahead the blocks are visible
+	Turbulence               // Turbulence make the plane and the bullets drift
+	Storm                    // Storm bring lightning strikes, and some turbulence
+)
+
+// logic constants
+const (
+	fogCover       = 0.45 // how much of the screen width the fog cover, at full strength
+	maxDrift       = 90   // turbulence drift, in design units per second, at full strength
+	stormTurbulent = 0.4  // turbulence in a storm
+	stormMin       = 0.5  // storm strength to start striking lightning
+	lightningDelay = 2.5  // seconds between lightning strikes, at full strength
+)
+
+// Names is our weather states names
+var Names = map[State]string{
+	Clear:      "clear",
+	Fog:        "fog",
+	Turbulence: "turbulence",
+	Storm:      "storm",
+}
+
+// Parse returns the State for a name, and if exist
+func Parse(name string) (State, bool) {
+	for state, n := range Names {
+		if n == name {
+			return state, true
+		}
+	}
+	return Clear, false
+}
+
+// Conditions is the strength of each weather effect, from 0 to 1
+type Conditions struct {
+	Fog        float32 // Fog strength
+	Turbulence float32 // Turbulence strength
+	Storm      float32 // Storm strength
+}
+
+// For returns the Conditions of a State at full strength
+func For(state State) Conditions {
+	switch state {
+	case Fog:
+		return Conditions{Fog: 1}
+	case Turbulence:
+		return Conditions{Turbulence: 1}
+	case Storm:
+		return Conditions{Turbulence: stormTurbulent, Storm: 1}
+	default:
+		return Conditions{}
+	}
+}
+
+// Towards returns the Conditions moved to others, changing each effect at most by step
+func (c Conditions) Towards(to Conditions, step float32) Conditions {
+	return Conditions{
+		Fog:        approach(c.Fog, to.Fog, step),
+		Turbulence: approach(c.Turbulence, to.Turbulence, step),
+		Storm:      approach(c.Storm, to.Storm, step),
+	}
+}
+
+// Visibility returns how much of the screen width is visible thru the fog, from 0 to 1
+func (c Conditions) Visibility() float32 {
+	return 1 - c.Fog*fogCover
+}
+
+// Drift returns the turbulence drift, in design units per second, at a time
+func (c Conditions) Drift(time float32) geometry.Point {
+	if c.Turbulence == 0 {
+		return geometry.Point{}
+	}
+	t := float64(time)
+	// mix some waves so gusts does not look regular
+	x := math.Sin(t*1.3) + 0.5*math.Sin(t*3.7)
+	y := math.Sin(t*0.9+1) + 0.5*math.Sin(t*2.3)
+	strength := maxDrift * c.Turbulence / 1.5
+	return geometry.Point{
+		X: float32(x) * strength,
+		Y: float32(y) * strength,
+	}
+}
+
+// Lightning returns if a lightning should strike, for the seconds since the last one
+func (c Conditions) Lightning(since float32) bool {
+	return c.Storm >= stormMin && since >= lightningDelay/c.Storm
+}
+
+// approach a value to a target, changing it at most by step
+func approach(value, target, step float32) float32 {
+	if value < target {
+		return float32(math.Min(float64(value+step), float64(target)))
+	}
+	return float32(math.Max(float64(value-step), float64(target)))
+}
@@ -0,0 +1,151 @@
+/*
+ * Copyright (c) 2020 Juan Medina.
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package weather
+
+import (
+	"fmt"
+	"github.com/juan-medina/gosge/components/geometry"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	type tc struct {
+		name   string
+		expect State
+		ok     bool
+	}
+
+	cases := []tc{
+		{name: "clear", expect: Clear, ok: true},
+		{name: "fog", expect: Fog, ok: true},
+		{name: "turbulence", expect: Turbulence, ok: true},
+		{name: "storm", expect: Storm, ok: true},
+		{name: "", expect: Clear, ok: false},
+		{name: "snow", expect: Clear, ok: false},
+	}
+
+	for i, v := range cases {
+		t.Run(fmt.Sprintf("case %d", i+1), func(t *testing.T) {
+			got, ok := Parse(v.name)
+			if got != v.expect || ok != v.ok {
+				t.Fatalf("parse error, got %v/%v, expect %v/%v", got, ok, v.expect, v.ok)
+			}
+		})
+	}
+}
+
+func TestConditions_Towards(t *testing.T) {
+	type tc struct {
+		from   Conditions
+		to     Conditions
+		step   float32
+		expect Conditions
+	}
+
+	cases := []tc{
+		{from: Conditions{}, to: For(Fog), step: 0.25, expect: Conditions{Fog: 0.25}},
+		{from: Conditions{Fog: 0.75}, to: For(Fog), step: 0.5, expect: Conditions{Fog: 1}},
+		{from: For(Fog), to: For(Clear), step: 0.5, expect: Conditions{Fog: 0.5}},
+		{from: For(Fog), to: For(Turbulence), step: 0.5, expect: Conditions{Fog: 0.5, Turbulence: 0.5}},
+		{from: For(Turbulence), to: For(Storm), step: 0.25, expect: Conditions{Turbulence: 0.75, Storm: 0.25}},
+		{from: For(Storm), to: For(Storm), step: 0.25, expect: For(Storm)},
+	}
+
+	for i, v := range cases {
+		t.Run(fmt.Sprintf("case %d", i+1), func(t *testing.T) {
+			if got := v.from.Towards(v.to, v.step); got != v.expect {
+				t.Fatalf("towards error, got %v, expect %v", got, v.expect)
+			}
+		})
+	}
+}
+
+func TestConditions_Visibility(t *testing.T) {
+	type tc struct {
+		conditions Conditions
+		expect     float32
+	}
+
+	cases := []tc{
+		{conditions: For(Clear), expect: 1},
+		{conditions: For(Storm), expect: 1},
+		{conditions: For(Fog), expect: 0.55},
+	}
+
+	for i, v := range cases {
+		t.Run(fmt.Sprintf("case %d", i+1), func(t *testing.T) {
+			if got := v.conditions.Visibility(); got != v.expect {
+				t.Fatalf("visibility error, got %v, expect %v", got, v.expect)
+			}
+		})
+	}
+}
+
+func TestConditions_Drift(t *testing.T) {
+	if got := For(Fog).Drift(3); got != (geometry.Point{}) {
+		t.Fatalf("drift error, got %v, expect %v", got, geometry.Point{})
+	}
+
+	for i := 0; i < 100; i++ {
+		time := float32(i) * 0.37
+		full := For(Turbulence).Drift(time)
+		if full.X < -maxDrift || full.X > maxDrift || full.Y < -maxDrift || full.Y > maxDrift {
+			t.Fatalf("drift error, got %v, expect under %v", full, maxDrift)
+		}
+		half := Conditions{Turbulence: 0.5}.Drift(time)
+		if !near(half.X*2, full.X) || !near(half.Y*2, full.Y) {
+			t.Fatalf("drift error, got %v, expect half of %v", half, full)
+		}
+	}
+}
+
+func TestConditions_Lightning(t *testing.T) {
+	type tc struct {
+		conditions Conditions
+		since      float32
+		expect     bool
+	}
+
+	cases := []tc{
+		{conditions: For(Clear), since: 100, expect: false},
+		{conditions: For(Turbulence), since: 100, expect: false},
+		{conditions: Conditions{Storm: 0.4}, since: 100, expect: false},
+		{conditions: For(Storm), since: 2, expect: false},
+		{conditions: For(Storm), since: 2.5, expect: true},
+		{conditions: Conditions{Storm: 0.5}, since: 4, expect: false},
+		{conditions: Conditions{Storm: 0.5}, since: 5, expect: true},
+	}
+
+	for i, v := range cases {
+		t.Run(fmt.Sprintf("case %d", i+1), func(t *testing.T) {
+			if got := v.conditions.Lightning(v.since); got != v.expect {
+				t.Fatalf("lightning error, got %v, expect %v", got, v.expect)
+			}
+		})
+	}
+}
+
+func near(a, b float32) bool {
+	d := a - b
+	return d > -0.001 && d < 0.001
+}
@@ -0,0 +1,112 @@
+/*
+ * Copyright (c) 2020 Juan Medina.
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package winning
+
+import (
+	"github.com/juan-medina/gosge/components/color"
+	"github.com/juan-medina/mesh2prod/game/constants"
+)
+
+// SLO is a service level objective that a delivery could meet
+type SLO struct {
+	Name     string      // Name of the SLO, for example "99.9%"
+	Color    color.Solid // Color for the SLO badge
+	MaxHits  int         // MaxHits is the max number of blocks that the plane and the mesh could hit
+	MinClear int         // MinClear is the min number of blocks that need to be clear
+	MaxTime  float32     // MaxTime is the max time, in seconds, to reach production, 0 for no limit
+}
+
+// Stats are the stats of a delivery
+type Stats struct {
+	Hits  int     // Hits is the number of blocks that the plane and the mesh has hit
+	Clear int     // Clear is the number of blocks that has been clear
+	Time  float32 // Time in seconds to reach production
+}
+
+// Grade is the SLO that a delivery has met, as index of the SLOs for a cloud size
+type Grade int
+
+// NoGrade is the Grade when a delivery does not met any SLO
+const NoGrade = Grade(-1)
+
+var (
+	gold   = color.Gold   // gold badge
+	silver = color.Gray   // silver badge
+	bronze = color.Orange // bronze badge
+
+	// SLOs are the objectives for each cloud size, from best to worst
+	SLOs = map[constants.CloudSize][]SLO{
+		constants.LocalCloud: {
+			{Name: "99.9%", Color: gold, MaxHits: 2, MinClear: 20, MaxTime: 130},
+			{Name: "99%", Color: silver, MaxHits: 8, MinClear: 10, MaxTime: 140},
+			{Name: "95%", Color: bronze, MaxHits: 15},
+		},
+		constants.StartupCloud: {
+			{Name: "99.9%", Color: gold, MaxHits: 4, MinClear: 60, MaxTime: 260},
+			{Name: "99%", Color: silver, MaxHits: 15, MinClear: 30, MaxTime: 280},
+			{Name: "95%", Color: bronze, MaxHits: 30},
+		},
+		constants.CorpCloud: {
+			{Name: "99.9%", Color: gold, MaxHits: 6, MinClear: 120, MaxTime: 450},
+			{Name: "99%", Color: silver, MaxHits: 20, MinClear: 60, MaxTime: 480},
+			{Name: "95%", Color: bronze, MaxHits: 40},
+		},
+		constants.PublicCloud: {
+			{Name: "99.9%", Color: gold, MaxHits: 8, MinClear: 180, MaxTime: 650},
+			{Name: "99%", Color: silver, MaxHits: 25, MinClear: 90, MaxTime: 690},
+			{Name: "95%", Color: bronze, MaxHits: 50},
+		},
+	}
+)
+
+// Met returns if the given Stats met this SLO
+func (slo SLO) Met(stats Stats) bool {
+	if stats.Hits > slo.MaxHits {
+		return false
+	}
+	if stats.Clear < slo.MinClear {
+		return false
+	}
+	if slo.MaxTime > 0 && stats.Time > slo.MaxTime {
+		return false
+	}
+	return true
+}
+
+// Better returns if this Grade is better than other Grade
+func (g Grade) Better(other Grade) bool {
+	if g == NoGrade {
+		return false
+	}
+	return other == NoGrade || g < other
+}
+
+// GradeDelivery returns the best Grade that the given Stats met from a set of SLOs
+func GradeDelivery(slos []SLO, stats Stats) Grade {
+	for i, slo := range slos {
+		if slo.Met(stats) {
+			return Grade(i)
+		}
+	}
+	return NoGrade
+}
@@ -0,0 +1,88 @@
+/*
+ * Copyright (c) 2020 Juan Medina.
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package winning
+
+import (
+	"fmt"
+	"testing"
+)
+
+var testSLOs = []SLO{
+	{Name: "99.9%", MaxHits: 2, MinClear: 20, MaxTime: 130},
+	{Name: "99%", MaxHits: 8, MinClear: 10, MaxTime: 140},
+	{Name: "95%", MaxHits: 15},
+}
+
+func TestGradeDelivery(t *testing.T) {
+	type tc struct {
+		given  Stats
+		expect Grade
+	}
+
+	cases := []tc{
+		{given: Stats{Hits: 0, Clear: 30, Time: 120}, expect: 0},
+		{given: Stats{Hits: 2, Clear: 20, Time: 130}, expect: 0},
+		{given: Stats{Hits: 3, Clear: 30, Time: 120}, expect: 1},
+		{given: Stats{Hits: 0, Clear: 15, Time: 120}, expect: 1},
+		{given: Stats{Hits: 0, Clear: 30, Time: 135}, expect: 1},
+		{given: Stats{Hits: 10, Clear: 0, Time: 1000}, expect: 2},
+		{given: Stats{Hits: 16, Clear: 100, Time: 100}, expect: NoGrade},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("case %d", i+1), func(t *testing.T) {
+			got := GradeDelivery(testSLOs, c.given)
+
+			if got != c.expect {
+				t.Fatalf("grade delivery error, got %v, expect %v", got, c.expect)
+			}
+		})
+	}
+}
+
+func TestGrade_Better(t *testing.T) {
+	type tc struct {
+		grade  Grade
+		other  Grade
+		expect bool
+	}
+
+	cases := []tc{
+		{grade: 0, other: 1, expect: true},
+		{grade: 2, other: 1, expect: false},
+		{grade: 1, other: 1, expect: false},
+		{grade: 2, other: NoGrade, expect: true},
+		{grade: NoGrade, other: 0, expect: false},
+		{grade: NoGrade, other: NoGrade, expect: false},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("case %d", i+1), func(t *testing.T) {
+			got := c.grade.Better(c.other)
+
+			if got != c.expect {
+				t.Fatalf("grade better error, got %v, expect %v", got, c.expect)
+			}
+		})
+	}
+}
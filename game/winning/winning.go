@@ -28,13 +28,18 @@ import (
 	"github.com/juan-medina/gosge"
 	"github.com/juan-medina/gosge/components/audio"
 	"github.com/juan-medina/gosge/components/color"
-	"github.com/juan-medina/gosge/components/device"
 	"github.com/juan-medina/gosge/components/effects"
 	"github.com/juan-medina/gosge/components/geometry"
 	"github.com/juan-medina/gosge/components/shapes"
 	"github.com/juan-medina/gosge/components/ui"
 	"github.com/juan-medina/gosge/events"
+	"github.com/juan-medina/mesh2prod/game/collision"
 	"github.com/juan-medina/mesh2prod/game/component"
+	"github.com/juan-medina/mesh2prod/game/constants"
+	"github.com/juan-medina/mesh2prod/game/input"
+	"github.com/juan-medina/mesh2prod/game/level"
+	"github.com/juan-medina/mesh2prod/game/pause"
+	"github.com/juan-medina/mesh2prod/game/upgrade"
 	"reflect"
 	"strings"
 )
@@ -50,13 +55,18 @@ const (
 	buttonExtraHeight = 0.20                             // the additional width for a button si it is not only the text size
 	clickSound        = "resources/audio/click.wav"      // button click sound
 	winSound          = "resources/audio/win.wav"        // win sound
+	rollbackSound     = "resources/audio/hit.wav"        // rollback sound
 	barWidth          = 300
 	barHeight         = 40
+	integrityBarWidth = 200 // mesh integrity bar width
+	partialIntegrity  = 0.5 // under this mesh integrity the delivery is partial
+	bossProgress      = 0.8 // progress to production when the boss stage starts
 )
 
 // FinalScoreEvent is trigger when the game ends
 type FinalScoreEvent struct {
-	Total int
+	Total   int // Total BlockCoins
+	Cleared int // Cleared is the number of blocks that has been clear
 }
 
 // FinalScoreEventType is the reflect.Type of FinalScoreEvent
@@ -66,20 +76,148 @@ var (
 	bcColor = color.Solid{R: 227, G: 140, B: 41, A: 255} // our bc text color
 )
 
+// Outcome is how a level has end
+type Outcome int
+
+// outcomes
+const (
+	Delivered  = Outcome(iota) // Delivered the mesh has reach production
+	RolledBack                 // RolledBack the deployment has fail
+)
+
+// Cause is why a level has end
+type Cause int
+
+// causes
+const (
+	ReachProduction = Cause(iota) // ReachProduction the mesh has reach production
+	PlaneDestroyed                // PlaneDestroyed the plane has lost all its integrity
+	MeshDestroyed                 // MeshDestroyed the mesh has lost all its integrity
+	Bankrupt                      // Bankrupt the BlockCoins owed are over MaxDebt
+	TimeOut                       // TimeOut the time limit has been reached
+	PartialDelivery               // PartialDelivery the mesh has reach production badly damaged
+)
+
+// MaxDebt is the BlockCoins that could be owed before being Bankrupt, so early hits do not end the level
+const MaxDebt = 100
+
+var (
+	// causeMessages is the text that we show for each cause
+	causeMessages = map[Cause]string{
+		ReachProduction: "Delivered to Prod!",
+		PlaneDestroyed:  "The plane was decommissioned",
+		MeshDestroyed:   "The mesh got corrupted",
+		Bankrupt:        "Out of BlockCoins",
+		TimeOut:         "Deployment timed out",
+		PartialDelivery: "Partially delivered to Prod",
+	}
+
+	integrityColors = ui.ProgressBarColor{
+		Gradient: color.Gradient{
+			From:      color.White,
+			To:        color.Green,
+			Direction: color.GradientHorizontal,
+		},
+		Border: color.DarkGreen,
+		Empty:  color.Maroon,
+	} // mesh integrity bar colors
+	damagedColors = ui.ProgressBarColor{
+		Gradient: color.Gradient{
+			From:      color.White,
+			To:        color.Red,
+			Direction: color.GradientHorizontal,
+		},
+		Border: color.Maroon,
+		Empty:  color.Maroon,
+	} // mesh integrity bar colors for a partial delivery
+)
+
 // LevelEndEvent is trigger when the level end
-type LevelEndEvent struct{}
+type LevelEndEvent struct {
+	Outcome Outcome // Outcome is how the level has end
+	Cause   Cause   // Cause is why the level has end
+}
 
 // LevelEndEventType is the reflect.Type of LevelEndEvent
 var LevelEndEventType = reflect.TypeOf(LevelEndEvent{})
 
+// RollbackEvent is trigger when the level need to fail
+type RollbackEvent struct {
+	Cause Cause // Cause is why the level fail
+}
+
+// RollbackEventType is the reflect.Type of RollbackEvent
+var RollbackEventType = reflect.TypeOf(RollbackEvent{})
+
+// RepairMeshEvent is a signal to repair the mesh integrity
+type RepairMeshEvent struct {
+	Blocks int // Blocks hits that will be repaired
+}
+
+// RepairMeshEventType is the reflect.Type of RepairMeshEvent
+var RepairMeshEventType = reflect.TypeOf(RepairMeshEvent{})
+
+// DamageMeshEvent is a signal to damage the integrity of a mesh in the convoy, without hitting a block
+type DamageMeshEvent struct {
+	Service int // Service is the mesh position in the convoy
+	Blocks  int // Blocks hits that the mesh take
+}
+
+// DamageMeshEventType is the reflect.Type of DamageMeshEvent
+var DamageMeshEventType = reflect.TypeOf(DamageMeshEvent{})
+
+// ServiceLostEvent is trigger when a mesh in the convoy has lost all its integrity
+type ServiceLostEvent struct {
+	Service int // Service is the mesh position in the convoy
+}
+
+// ServiceLostEventType is the reflect.Type of ServiceLostEvent
+var ServiceLostEventType = reflect.TypeOf(ServiceLostEvent{})
+
+// ProgressEvent is trigger when the convoy progress to production changes
+type ProgressEvent struct {
+	Percent float32 // Percent of the distance to production, from 0 to 1
+}
+
+// ProgressEventType is the reflect.Type of ProgressEvent
+var ProgressEventType = reflect.TypeOf(ProgressEvent{})
+
+// BossStageEvent is trigger when the convoy is close to production, and the boss stage starts
+type BossStageEvent struct {
+	Phases int // Phases is the number of phases of the boss
+}
+
+// BossStageEventType is the reflect.Type of BossStageEvent
+var BossStageEventType = reflect.TypeOf(BossStageEvent{})
+
+// BossDefeatedEvent is trigger when the boss has been defeated, and the way to production is open
+type BossDefeatedEvent struct{}
+
+// BossDefeatedEventType is the reflect.Type of BossDefeatedEvent
+var BossDefeatedEventType = reflect.TypeOf(BossDefeatedEvent{})
+
 type winningSystem struct {
-	gs       geometry.Scale
-	dr       geometry.Size
-	eng      *gosge.Engine
-	end      bool
-	label    *goecs.Entity
-	prodBar  *goecs.Entity
-	distance float32
+	pause.State
+	gs           geometry.Scale
+	dr           geometry.Size
+	eng          *gosge.Engine
+	end          bool
+	label        *goecs.Entity
+	prodBar      *goecs.Entity
+	integrityBar *goecs.Entity // mesh integrity bar
+	timeLabel    *goecs.Entity
+	distance     float32
+	timeLimit    float32       // time limit in seconds, 0 for no limit
+	time         float32       // time since the level start
+	planeHits    int           // blocks that the plane has hit
+	meshHits     []int         // blocks that each mesh in the convoy has hit
+	lost         []bool        // meshes in the convoy that has been lost
+	levelEnd     LevelEndEvent // how the level has end
+	lastRemain   int           // last remaining seconds displayed
+	panel        geometry.Rect // the message panel
+	lvl          level.Level   // our level
+	boss         bool          // has the boss stage started
+	progress     float32       // last progress to production
 }
 
 // add the background
@@ -101,6 +239,14 @@ func (ws *winningSystem) load(eng *gosge.Engine) error {
 		return err
 	}
 
+	// pre-load rollback sound
+	if err = eng.LoadSound(rollbackSound); err != nil {
+		return err
+	}
+
+	// apply the mesh armour upgrade
+	ws.lvl.MeshIntegrity += int(upgrade.MeshArmour.Value(eng.GetSettings()))
+
 	// get the ECS world
 	world := eng.World()
 
@@ -156,55 +302,357 @@ func (ws *winningSystem) load(eng *gosge.Engine) error {
 		effects.Layer{Depth: -100},
 	)
 
+	// add the mesh integrity, next to production
+	integrityPos := geometry.Point{
+		X: pos.X + (barWidth*0.5+10)*ws.gs.Max,
+		Y: 5 * ws.gs.Max,
+	}
+
+	ws.integrityBar = world.AddEntity(
+		ui.ProgressBar{
+			Min:     0,
+			Max:     1,
+			Current: 1,
+			Shadow: geometry.Size{
+				Width:  5 * ws.gs.Max,
+				Height: 5 * ws.gs.Max,
+			},
+		},
+		integrityPos,
+		shapes.Box{
+			Size: geometry.Size{
+				Width:  integrityBarWidth,
+				Height: barHeight,
+			},
+			Scale:     ws.gs.Max,
+			Thickness: int32(2 * ws.gs.Max),
+		},
+		integrityColors,
+		effects.Layer{Depth: -100},
+	)
+
+	pos.X += (barWidth*0.5 + 10 + integrityBarWidth*0.5) * ws.gs.Max
+
+	world.AddEntity(
+		ui.Text{
+			String:     "Integrity",
+			Size:       fontSmall * ws.gs.Max,
+			Font:       font,
+			VAlignment: ui.MiddleVAlignment,
+			HAlignment: ui.CenterHAlignment,
+		},
+		pos,
+		color.White,
+		effects.Layer{Depth: -100},
+	)
+
+	// if we have a time limit display the remaining time
+	if ws.timeLimit > 0 {
+		pos.X += (integrityBarWidth*0.5 + 10) * ws.gs.Max
+
+		ws.timeLabel = world.AddEntity(
+			ui.Text{
+				String:     formatTime(ws.timeLimit),
+				Size:       fontSmall * ws.gs.Max,
+				Font:       font,
+				VAlignment: ui.MiddleVAlignment,
+				HAlignment: ui.LeftHAlignment,
+			},
+			pos,
+			color.White,
+			effects.Layer{Depth: -100},
+		)
+
+	}
+
+	// count the time
+	world.AddSystem(ws.timeSystem)
+
 	// calculate when we reach production
 	world.AddSystem(ws.reachProductionSystem)
 
+	// listen to collisions
+	world.AddListener(ws.collisionListener, collision.PlaneHitBlockEventType, collision.PlaneHitEnemyEventType,
+		collision.PlaneHitHazardEventType, collision.MeshHitBlockEventType, RepairMeshEventType, DamageMeshEventType)
+
+	// listen to rollbacks
+	world.AddListener(ws.rollbackListener, RollbackEventType)
+
+	// listen to next level
+	world.AddListener(ws.nextLevelListener, nextLevelEventType)
+
 	// final score listener
 	world.AddListener(ws.finalScoreListener, FinalScoreEventType)
 
 	// update prod system
 	world.AddSystem(ws.updateProdBar)
 
-	// listen to keys
-	world.AddListener(ws.KeysListener, events.TYPE.KeyUpEvent)
+	// listen to actions
+	world.AddListener(ws.actionListener, input.ActionEventType)
 
-	// listen to gamepad
-	world.AddListener(ws.gamepadListener, events.TYPE.GamePadButtonUpEvent)
+	// keep track of the pause
+	ws.Track(world)
 
 	return nil
 }
 
 func (ws *winningSystem) reachProductionSystem(world *goecs.World, _ float32) error {
-	if ws.end {
+	if ws.end || ws.IsPaused() {
 		return nil
 	}
 
-	mesh := world.Iterator(component.TYPE.Mesh).Value()
 	prod := world.Iterator(component.TYPE.Production).Value()
-
-	meshPos := geometry.Get.Point(mesh)
 	prodPos := geometry.Get.Point(prod)
 
-	diffX := prodPos.X - meshPos.X
+	// the convoy reach production when the last mesh does
+	last, _, ok := ws.convoyPos(world)
+	if !ok {
+		return nil
+	}
+
+	diffX := prodPos.X - last.X
 	if diffX < 0 {
-		ws.end = true
-		world.Signal(LevelEndEvent{})
-		for it := world.Iterator(audio.TYPE.MusicState); it != nil; it = it.Next() {
-			val := it.Value()
-			sta := audio.Get.MusicState(val)
-			if sta.PlayingState == audio.StatePlaying {
-				if !strings.Contains(sta.Name, "plane") {
-					world.Signal(events.StopMusicEvent{Name: sta.Name})
-					break
-				}
+		// a convoy that has lost services, or is badly damaged, is only partially delivered
+		cause := ReachProduction
+		if ws.delivered() < len(ws.lost) || ws.integrity() < partialIntegrity {
+			cause = PartialDelivery
+		}
+		ws.endLevel(world, LevelEndEvent{Outcome: Delivered, Cause: cause})
+	}
+
+	return nil
+}
+
+// the position of the last and the first meshes in the convoy, and if there is any left
+func (ws winningSystem) convoyPos(world *goecs.World) (last geometry.Point, first geometry.Point, ok bool) {
+	for it := world.Iterator(component.TYPE.Mesh, geometry.TYPE.Point); it != nil; it = it.Next() {
+		pos := geometry.Get.Point(it.Value())
+		if !ok || pos.X < last.X {
+			last = pos
+		}
+		if !ok || pos.X > first.X {
+			first = pos
+		}
+		ok = true
+	}
+	return
+}
+
+// the number of services that has not been lost
+func (ws winningSystem) delivered() int {
+	total := 0
+	for _, lost := range ws.lost {
+		if !lost {
+			total++
+		}
+	}
+	return total
+}
+
+// end the level with a given result
+func (ws *winningSystem) endLevel(world *goecs.World, levelEnd LevelEndEvent) {
+	ws.end = true
+	ws.levelEnd = levelEnd
+	world.Signal(levelEnd)
+
+	// a delivery unlock the next level
+	if levelEnd.Outcome == Delivered {
+		ws.lvl.Unlock(ws.eng.GetSettings())
+	}
+	for it := world.Iterator(audio.TYPE.MusicState); it != nil; it = it.Next() {
+		val := it.Value()
+		sta := audio.Get.MusicState(val)
+		if sta.PlayingState == audio.StatePlaying {
+			if !strings.Contains(sta.Name, "plane") {
+				world.Signal(events.StopMusicEvent{Name: sta.Name})
+				break
 			}
 		}
 	}
+}
+
+// count the time and check the time limit
+func (ws *winningSystem) timeSystem(world *goecs.World, delta float32) error {
+	if ws.end || ws.IsPaused() {
+		return nil
+	}
+
+	ws.time += delta
+
+	if ws.timeLimit == 0 {
+		return nil
+	}
+
+	remain := ws.timeLimit - ws.time
+
+	if remain <= 0 {
+		remain = 0
+		world.Signal(RollbackEvent{Cause: TimeOut})
+	}
+
+	// update the text only when the seconds changes
+	if int(remain) != ws.lastRemain {
+		ws.lastRemain = int(remain)
+		text := ui.Get.Text(ws.timeLabel)
+		text.String = formatTime(remain)
+		ws.timeLabel.Set(text)
+		if remain < 30 {
+			ws.timeLabel.Set(color.Red)
+		}
+	}
 
 	return nil
 }
 
+// count the blocks that the plane and mesh hit, and the mesh repairs and damage
+func (ws *winningSystem) collisionListener(world *goecs.World, signal interface{}, _ float32) error {
+	if ws.end {
+		return nil
+	}
+	switch e := signal.(type) {
+	case collision.PlaneHitBlockEvent, collision.PlaneHitEnemyEvent, collision.PlaneHitHazardEvent:
+		if ws.planeHits++; ws.planeHits >= ws.lvl.PlaneIntegrity {
+			world.Signal(RollbackEvent{Cause: PlaneDestroyed})
+		}
+	case collision.MeshHitBlockEvent:
+		ws.damageService(world, e.Service, 1)
+	case DamageMeshEvent:
+		ws.damageService(world, e.Service, e.Blocks)
+	case RepairMeshEvent:
+		// repair the most damaged service
+		worst := -1
+		for i, hits := range ws.meshHits {
+			if !ws.lost[i] && (worst == -1 || hits > ws.meshHits[worst]) {
+				worst = i
+			}
+		}
+		if worst != -1 {
+			if ws.meshHits[worst] -= e.Blocks; ws.meshHits[worst] < 0 {
+				ws.meshHits[worst] = 0
+			}
+		}
+	}
+	ws.updateIntegrityBar()
+	return nil
+}
+
+// damage a service in the convoy, it is lost when it has no integrity left, and we fail if we lost all of them
+func (ws *winningSystem) damageService(world *goecs.World, service, blocks int) {
+	if service < 0 || service >= len(ws.meshHits) || ws.lost[service] {
+		return
+	}
+	if ws.meshHits[service] += blocks; ws.meshHits[service] >= ws.lvl.MeshIntegrity {
+		ws.lost[service] = true
+		world.Signal(ServiceLostEvent{Service: service})
+		if ws.delivered() == 0 {
+			world.Signal(RollbackEvent{Cause: MeshDestroyed})
+		}
+	}
+}
+
+// the convoy integrity left, from 0 to 1
+func (ws winningSystem) integrity() float32 {
+	return ConvoyIntegrity(ws.meshHits, ws.lost, ws.lvl.MeshIntegrity)
+}
+
+// update the mesh integrity bar, and its colors if the delivery will be partial
+func (ws winningSystem) updateIntegrityBar() {
+	bar := ui.Get.ProgressBar(ws.integrityBar)
+	bar.Current = ws.integrity()
+	ws.integrityBar.Set(bar)
+	if bar.Current < partialIntegrity {
+		ws.integrityBar.Set(damagedColors)
+	} else {
+		ws.integrityBar.Set(integrityColors)
+	}
+}
+
+// Integrity returns the integrity left, from 0 to 1, after a number of hits
+func Integrity(hits, integrity int) float32 {
+	if integrity <= 0 || hits >= integrity {
+		return 0
+	}
+	if hits <= 0 {
+		return 1
+	}
+	return 1 - float32(hits)/float32(integrity)
+}
+
+// ConvoyIntegrity returns the average integrity left in a convoy, from 0 to 1, the lost services count as 0
+func ConvoyIntegrity(hits []int, lost []bool, integrity int) float32 {
+	if len(hits) == 0 {
+		return 0
+	}
+	total := float32(0)
+	for i, h := range hits {
+		if !lost[i] {
+			total += Integrity(h, integrity)
+		}
+	}
+	return total / float32(len(hits))
+}
+
+// PartialPayout returns the BlockCoins that we get for a partial delivery with an integrity
+func PartialPayout(total int, integrity float32) int {
+	if total <= 0 {
+		return total
+	}
+	return int(float32(total) * integrity)
+}
+
+// go to the next level
+func (ws *winningSystem) nextLevelListener(world *goecs.World, signal interface{}, _ float32) error {
+	switch signal.(type) {
+	case nextLevelEvent:
+		if next, ok := ws.lvl.Next(); ok {
+			level.Select(ws.eng.GetSettings(), next)
+		}
+		world.Signal(events.ChangeGameStage{Stage: "game"})
+	}
+	return nil
+}
+
+// fail the level
+func (ws *winningSystem) rollbackListener(world *goecs.World, signal interface{}, _ float32) error {
+	if ws.end {
+		return nil
+	}
+	switch e := signal.(type) {
+	case RollbackEvent:
+		ws.endLevel(world, LevelEndEvent{Outcome: RolledBack, Cause: e.Cause})
+	}
+	return nil
+}
+
 func (ws *winningSystem) addMessage(world *goecs.World) error {
+	title := causeMessages[ws.levelEnd.Cause]
+	retry := "Redeploy"
+	var retryEvent interface{} = events.ChangeGameStage{Stage: "game"}
+
+	// if we deliver and we have a next level we go to it
+	if _, ok := ws.lvl.Next(); ok && ws.levelEnd.Outcome == Delivered {
+		retry = "Next"
+		retryEvent = nextLevelEvent{}
+	}
+	gradient := color.Gradient{
+		From:      color.DarkBlue.Alpha(210),
+		To:        color.SkyBlue.Alpha(190),
+		Direction: color.GradientVertical,
+	}
+	border := color.DarkBlue
+
+	// a failed deployment get a rollback panel
+	if ws.levelEnd.Outcome == RolledBack {
+		title = "Rollback initiated"
+		retry = "Retry"
+		gradient = color.Gradient{
+			From:      color.Maroon.Alpha(210),
+			To:        color.Red.Alpha(190),
+			Direction: color.GradientVertical,
+		}
+		border = color.Maroon
+	}
+
 	boxSize := geometry.Size{
 		Width:  ws.dr.Width * 0.35,
 		Height: ws.dr.Height * 0.25,
@@ -220,16 +668,17 @@ func (ws *winningSystem) addMessage(world *goecs.World) error {
 		Y: boxPos.Y + (10 * ws.gs.Max),
 	}
 
+	ws.panel = geometry.Rect{
+		From: boxPos,
+		Size: boxSize,
+	}
+
 	world.AddEntity(
 		shapes.SolidBox{
 			Size:  boxSize,
 			Scale: ws.gs.Max,
 		},
-		color.Gradient{
-			From:      color.DarkBlue.Alpha(210),
-			To:        color.SkyBlue.Alpha(190),
-			Direction: color.GradientVertical,
-		},
+		gradient,
 		boxPos,
 		effects.Layer{Depth: -2},
 	)
@@ -239,14 +688,14 @@ func (ws *winningSystem) addMessage(world *goecs.World) error {
 			Scale:     ws.gs.Max,
 			Thickness: int32(2 * ws.gs.Max),
 		},
-		color.DarkBlue,
+		border,
 		boxPos,
 		effects.Layer{Depth: -2},
 	)
 
 	world.AddEntity(
 		ui.Text{
-			String:     "Delivered to Prod!",
+			String:     title,
 			Size:       fontSize * ws.gs.Max,
 			Font:       font,
 			VAlignment: ui.TopVAlignment,
@@ -292,7 +741,7 @@ func (ws *winningSystem) addMessage(world *goecs.World) error {
 		ui.FlatButton{
 			Shadow: geometry.Size{Width: shadowExtraWidth * ws.gs.Max, Height: shadowExtraHeight * ws.gs.Max},
 			Event: events.DelaySignal{
-				Signal: events.ChangeGameStage{Stage: "game"},
+				Signal: retryEvent,
 				Time:   0.25,
 			},
 			Sound:  clickSound,
@@ -308,7 +757,7 @@ func (ws *winningSystem) addMessage(world *goecs.World) error {
 			Thickness: int32(2 * ws.gs.Max),
 		},
 		ui.Text{
-			String:     "Redeploy",
+			String:     retry,
 			Size:       fontButtonSize * ws.gs.Max,
 			Font:       font,
 			VAlignment: ui.MiddleVAlignment,
@@ -334,7 +783,7 @@ func (ws *winningSystem) addMessage(world *goecs.World) error {
 		ui.FlatButton{
 			Shadow: geometry.Size{Width: shadowExtraWidth * ws.gs.Max, Height: shadowExtraHeight * ws.gs.Max},
 			Event: events.DelaySignal{
-				Signal: events.ChangeGameStage{Stage: "menu"},
+				Signal: events.ChangeGameStage{Stage: "campaign"},
 				Time:   0.25,
 			},
 			Sound:  clickSound,
@@ -377,39 +826,84 @@ func (ws *winningSystem) finalScoreListener(world *goecs.World, signal interface
 			return err
 		}
 		text := ui.Get.Text(ws.label)
-		text.String = fmt.Sprintf("You got %d BlockCoins", e.Total)
+		if ws.levelEnd.Outcome == RolledBack {
+			text.String = causeMessages[ws.levelEnd.Cause]
+			ws.label.Set(text)
+			world.Signal(events.PlaySoundEvent{Name: rollbackSound, Volume: 1})
+			return nil
+		}
+		// a partial delivery only pays for the integrity left
+		total := e.Total
+		if ws.levelEnd.Cause == PartialDelivery {
+			total = PartialPayout(total, ws.integrity())
+		}
+		// add the coins to the wallet
+		upgrade.Deposit(ws.eng.GetSettings(), total)
+		text.String = fmt.Sprintf("You got %d BlockCoins", total)
+		if len(ws.lost) > 1 {
+			text.String = fmt.Sprintf("%d/%d services, %d BlockCoins", ws.delivered(), len(ws.lost), total)
+		}
 		ws.label.Set(text)
 		world.Signal(events.PlaySoundEvent{Name: winSound, Volume: 1})
+
+		// grade the delivery
+		stats := Stats{
+			Hits:  ws.planeHits + ws.totalMeshHits(),
+			Clear: e.Cleared,
+			Time:  ws.time,
+		}
+		ws.gradeDelivery(world, stats)
 	}
 	return nil
 }
 
-func (ws *winningSystem) KeysListener(world *goecs.World, signal interface{}, _ float32) error {
+// the blocks that all the meshes in the convoy has hit
+func (ws winningSystem) totalMeshHits() int {
+	total := 0
+	for _, hits := range ws.meshHits {
+		total += hits
+	}
+	return total
+}
+
+func (ws *winningSystem) actionListener(world *goecs.World, signal interface{}, _ float32) error {
 	switch e := signal.(type) {
-	case events.KeyUpEvent:
-		if e.Key == device.KeyEscape {
-			world.Signal(events.PlaySoundEvent{Name: clickSound, Volume: 1})
-			world.Signal(events.DelaySignal{
-				Signal: events.ChangeGameStage{Stage: "menu"},
-				Time:   0.25,
-			})
+	case input.ActionEvent:
+		if e.Action == input.Pause && !e.Pressed {
+			ws.escape(world)
 		}
 	}
 	return nil
 }
 
-func (ws *winningSystem) updateProdBar(world *goecs.World, _ float32) error {
+// escape goes back to the campaign if the level has end, otherwise toggle the pause
+func (ws *winningSystem) escape(world *goecs.World) {
+	world.Signal(events.PlaySoundEvent{Name: clickSound, Volume: 1})
 	if ws.end {
+		world.Signal(events.DelaySignal{
+			Signal: events.ChangeGameStage{Stage: "campaign"},
+			Time:   0.25,
+		})
+		return
+	}
+	world.Signal(pause.ToggleEvent{})
+}
+
+func (ws *winningSystem) updateProdBar(world *goecs.World, _ float32) error {
+	if ws.end || ws.IsPaused() {
 		return nil
 	}
 
-	mesh := world.Iterator(component.TYPE.Mesh).Value()
 	prod := world.Iterator(component.TYPE.Production).Value()
-
-	meshPos := geometry.Get.Point(mesh)
 	prodPos := geometry.Get.Point(prod)
 
-	diff := prodPos.X - meshPos.X
+	// the progress is for the first mesh in the convoy
+	_, first, ok := ws.convoyPos(world)
+	if !ok {
+		return nil
+	}
+
+	diff := prodPos.X - first.X
 
 	if ws.distance == 0 {
 		ws.distance = diff
@@ -421,29 +915,119 @@ func (ws *winningSystem) updateProdBar(world *goecs.World, _ float32) error {
 	bar.Current = percent
 	ws.prodBar.Set(bar)
 
+	if percent != ws.progress {
+		ws.progress = percent
+		world.Signal(ProgressEvent{Percent: percent})
+	}
+
+	// close to production the boss stage starts
+	if !ws.boss && ws.lvl.BossPhases > 0 && percent >= bossProgress {
+		ws.boss = true
+		world.Signal(BossStageEvent{Phases: ws.lvl.BossPhases})
+	}
+
 	return nil
 }
 
-func (ws *winningSystem) gamepadListener(world *goecs.World, signal interface{}, _ float32) error {
-	switch v := signal.(type) {
-	case events.GamePadButtonUpEvent:
-		if v.Button == device.GamepadSelect {
-			world.Signal(events.PlaySoundEvent{Name: clickSound, Volume: 1})
-			world.Signal(events.DelaySignal{
-				Signal: events.ChangeGameStage{Stage: "menu"},
-				Time:   0.25,
-			})
-		}
+// grade a delivery, show the badge and save the best grade
+func (ws *winningSystem) gradeDelivery(world *goecs.World, stats Stats) {
+	slos := SLOs[ws.lvl.Cloud]
+	grade := GradeDelivery(slos, stats)
+
+	// check and save our best grade
+	setting := fmt.Sprintf(constants.BestGradeConfig, ws.lvl.Key())
+	best := Grade(ws.eng.GetSettings().GetIn32(setting, int32(NoGrade)))
+	newBest := grade.Better(best)
+	if newBest {
+		ws.eng.GetSettings().SetInt32(setting, int32(grade))
 	}
-	return nil
+
+	text := "SLO breached"
+	clr := color.Red
+	if grade != NoGrade {
+		text = "SLO " + slos[grade].Name
+		clr = slos[grade].Color
+	}
+
+	badgeSize := geometry.Size{
+		Width:  200,
+		Height: 50,
+	}
+
+	// the badge is a stamp on the top right corner of the panel
+	badgePos := geometry.Point{
+		X: ws.panel.From.X + ((ws.panel.Size.Width - (badgeSize.Width * 0.75)) * ws.gs.Max),
+		Y: ws.panel.From.Y - (badgeSize.Height * 0.5 * ws.gs.Max),
+	}
+
+	badge := world.AddEntity(
+		shapes.SolidBox{
+			Size:  badgeSize,
+			Scale: ws.gs.Max,
+		},
+		clr,
+		badgePos,
+		effects.Layer{Depth: -3},
+	)
+
+	// a new best grade will blink
+	if newBest {
+		badge.Add(effects.AlternateColor{
+			From:  clr,
+			To:    color.White,
+			Time:  0.35,
+			Delay: 0.35,
+		})
+	}
+
+	world.AddEntity(
+		shapes.Box{
+			Size:      badgeSize,
+			Scale:     ws.gs.Max,
+			Thickness: int32(2 * ws.gs.Max),
+		},
+		color.White,
+		badgePos,
+		effects.Layer{Depth: -3},
+	)
+
+	world.AddEntity(
+		ui.Text{
+			String:     text,
+			Size:       fontButtonSize * ws.gs.Max,
+			Font:       font,
+			VAlignment: ui.MiddleVAlignment,
+			HAlignment: ui.CenterHAlignment,
+		},
+		geometry.Point{
+			X: badgePos.X + (badgeSize.Width * 0.5 * ws.gs.Max),
+			Y: badgePos.Y + (badgeSize.Height * 0.5 * ws.gs.Max),
+		},
+		color.DarkBlue,
+		effects.Layer{Depth: -3},
+	)
 }
 
-// System creates the mesh system
-func System(engine *gosge.Engine, gs geometry.Scale, dr geometry.Size) error {
+// format a time in seconds as minutes and seconds
+func formatTime(seconds float32) string {
+	return fmt.Sprintf("%02d:%02d", int(seconds)/60, int(seconds)%60)
+}
+
+// System creates the winning system for a level.Level
+func System(engine *gosge.Engine, gs geometry.Scale, dr geometry.Size, lvl level.Level) error {
 	ws := winningSystem{
-		gs:  gs,
-		dr:  dr,
-		eng: engine,
+		gs:         gs,
+		dr:         dr,
+		eng:        engine,
+		timeLimit:  lvl.TimeLimit,
+		lastRemain: -1,
+		lvl:        lvl,
+		meshHits:   make([]int, lvl.Services),
+		lost:       make([]bool, lvl.Services),
 	}
 	return ws.load(engine)
 }
+
+type nextLevelEvent struct{}
+
+var nextLevelEventType = reflect.TypeOf(nextLevelEvent{})
@@ -0,0 +1,100 @@
+/*
+ * Copyright (c) 2020 Juan Medina.
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package winning
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestIntegrity(t *testing.T) {
+	type tc struct {
+		hits      int
+		integrity int
+		expect    float32
+	}
+
+	cases := []tc{
+		{hits: 0, integrity: 40, expect: 1},
+		{hits: 10, integrity: 40, expect: 0.75},
+		{hits: 40, integrity: 40, expect: 0},
+		{hits: 50, integrity: 40, expect: 0},
+		{hits: 0, integrity: 0, expect: 0},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("case %d", i+1), func(t *testing.T) {
+			got := Integrity(c.hits, c.integrity)
+			if got != c.expect {
+				t.Fatalf("integrity error, got %v, expect %v", got, c.expect)
+			}
+		})
+	}
+}
+
+func TestConvoyIntegrity(t *testing.T) {
+	type tc struct {
+		hits   []int
+		lost   []bool
+		expect float32
+	}
+
+	cases := []tc{
+		{hits: []int{0}, lost: []bool{false}, expect: 1},
+		{hits: []int{10, 30}, lost: []bool{false, false}, expect: 0.5},
+		{hits: []int{0, 40}, lost: []bool{false, true}, expect: 0.5},
+		{hits: []int{}, lost: []bool{}, expect: 0},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("case %d", i+1), func(t *testing.T) {
+			got := ConvoyIntegrity(c.hits, c.lost, 40)
+			if got != c.expect {
+				t.Fatalf("convoy integrity error, got %v, expect %v", got, c.expect)
+			}
+		})
+	}
+}
+
+func TestPartialPayout(t *testing.T) {
+	type tc struct {
+		total     int
+		integrity float32
+		expect    int
+	}
+
+	cases := []tc{
+		{total: 100, integrity: 0.25, expect: 25},
+		{total: 100, integrity: 0, expect: 0},
+		{total: -10, integrity: 0.25, expect: -10},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("case %d", i+1), func(t *testing.T) {
+			got := PartialPayout(c.total, c.integrity)
+			if got != c.expect {
+				t.Fatalf("partial payout error, got %v, expect %v", got, c.expect)
+			}
+		})
+	}
+}
@@ -23,6 +23,7 @@
 package main
 
 import (
+	"flag"
 	"os"
 	"path/filepath"
 
@@ -30,9 +31,13 @@ import (
 	"github.com/juan-medina/gosge/components/color"
 	"github.com/juan-medina/gosge/events"
 	"github.com/juan-medina/gosge/options"
+	"github.com/juan-medina/mesh2prod/campaign"
 	"github.com/juan-medina/mesh2prod/game"
+	"github.com/juan-medina/mesh2prod/game/constants"
+	"github.com/juan-medina/mesh2prod/game/weather"
 	"github.com/juan-medina/mesh2prod/intro"
 	"github.com/juan-medina/mesh2prod/menu"
+	"github.com/juan-medina/mesh2prod/shop"
 	"github.com/rs/zerolog/log"
 )
 
@@ -52,16 +57,30 @@ var opt = options.Options{
 	// Height:   1536,
 }
 
+// weather state to force in the levels, for testing
+var forceWeather = flag.String("weather", "", "force the weather state: clear, fog, turbulence or storm")
+
 func load(eng *gosge.Engine) error {
 	eng.GetSettings().SetString("version", version)
+	// settings are saved, so we always set the forced weather to only last this run
+	eng.GetSettings().SetString(constants.WeatherConfig, *forceWeather)
 	eng.AddGameStage("game", game.Stage)
 	eng.AddGameStage("menu", menu.Stage)
+	eng.AddGameStage("campaign", campaign.Stage)
+	eng.AddGameStage("shop", shop.Stage)
 	eng.AddGameStage("intro", intro.Stage)
 	eng.World().Signal(events.ChangeGameStage{Stage: "intro"})
 	return nil
 }
 
 func main() {
+	flag.Parse()
+	// check that we know the weather state to force
+	if *forceWeather != "" {
+		if _, ok := weather.Parse(*forceWeather); !ok {
+			log.Fatal().Str("weather", *forceWeather).Msg("unknown weather state")
+		}
+	}
 	var err error = nil
 	// if we can not find the resources folder
 	if _, err = os.Stat("resources"); os.IsNotExist(err) {
@@ -0,0 +1,428 @@
+/*
+ * Copyright (c) 2020 Juan Medina.
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package menu
+
+import (
+	"fmt"
+	"github.com/juan-medina/goecs"
+	"github.com/juan-medina/gosge"
+	"github.com/juan-medina/gosge/components/color"
+	"github.com/juan-medina/gosge/components/effects"
+	"github.com/juan-medina/gosge/components/geometry"
+	"github.com/juan-medina/gosge/components/shapes"
+	"github.com/juan-medina/gosge/components/ui"
+	"github.com/juan-medina/gosge/events"
+	"github.com/juan-medina/mesh2prod/game/input"
+	"reflect"
+)
+
+const (
+	controlsMenu    = "controls" // controls menu
+	controlsRowSize = 55         // height of each row in the controls menu
+	maxDeadZone     = 50         // max dead zone in percentage
+	waitingText     = "..."      // text while waiting for a key or button
+)
+
+var (
+	mapping         input.Mapping                      // current input mapping
+	keyButtons      = map[input.Action]*goecs.Entity{} // buttons for the keys of each action
+	padButtons      = map[input.Action]*goecs.Entity{} // buttons for the gamepad of each action
+	deadZoneBar     *goecs.Entity                      // dead zone progress bar
+	deadZoneText    *goecs.Entity                      // dead zone label
+	schemeButton    *goecs.Entity                      // steering scheme button
+	targetingButton *goecs.Entity                      // targeting mode button
+	rebinding       *rebindEvent                       // the rebind we are waiting for, if any
+)
+
+func createControlsMenu(eng *gosge.Engine, world *goecs.World, dr geometry.Size, gs geometry.Scale) error {
+	mapping = input.Load(eng.GetSettings())
+	rebinding = nil
+
+	panelSize := geometry.Size{
+		Width:  700,
+		Height: 220 + float32((len(input.Actions)+2)*controlsRowSize),
+	}
+
+	panelPos := geometry.Point{
+		X: (dr.Width * gs.Point.X * 0.5) - (panelSize.Width * gs.Max * 0.5),
+		Y: (dr.Height * gs.Point.Y * 0.5) - (panelSize.Height * gs.Max * 0.5),
+	}
+
+	world.AddEntity(
+		shapes.SolidBox{
+			Size:  panelSize,
+			Scale: gs.Max,
+		},
+		panelPos,
+		color.Black.Alpha(90),
+		menu{name: controlsMenu},
+		effects.Hide{},
+	)
+	world.AddEntity(
+		shapes.Box{
+			Size:      panelSize,
+			Scale:     gs.Max,
+			Thickness: int32(menuControlBorder * gs.Max),
+		},
+		panelPos,
+		color.White,
+		menu{name: controlsMenu},
+		effects.Hide{},
+	)
+
+	world.AddEntity(
+		ui.Text{
+			String:     "Controls",
+			Size:       fontBigSize * gs.Max,
+			Font:       font,
+			VAlignment: ui.MiddleVAlignment,
+			HAlignment: ui.CenterHAlignment,
+		},
+		geometry.Point{
+			X: panelPos.X + (panelSize.Width * 0.5 * gs.Max),
+			Y: panelPos.Y + (40 * gs.Max),
+		},
+		color.White,
+		menu{name: controlsMenu},
+		effects.Hide{},
+	)
+
+	buttonSize := geometry.Size{
+		Width:  200,
+		Height: 45,
+	}
+
+	rowPos := geometry.Point{
+		X: panelPos.X + (20 * gs.Max),
+		Y: panelPos.Y + (90 * gs.Max),
+	}
+
+	// a row per action, with its key and gamepad button
+	for i, a := range input.Actions {
+		addControlLabel(world, gs, input.ActionNames[a], rowPos, buttonSize.Height)
+
+		keyPos := geometry.Point{
+			X: rowPos.X + (230 * gs.Max),
+			Y: rowPos.Y,
+		}
+		keyButtons[a] = addControlButton(world, gs, input.KeyName(mapping.Bindings[a].Key), keyPos, buttonSize,
+			rebindEvent{action: a}, i == 0)
+
+		padPos := geometry.Point{
+			X: keyPos.X + ((buttonSize.Width + 20) * gs.Max),
+			Y: rowPos.Y,
+		}
+		padButtons[a] = addControlButton(world, gs, input.ButtonName(mapping.Bindings[a].Button), padPos, buttonSize,
+			rebindEvent{action: a, pad: true}, false)
+
+		rowPos.Y += controlsRowSize * gs.Max
+	}
+
+	// steering scheme
+	addControlLabel(world, gs, "Steering", rowPos, buttonSize.Height)
+
+	schemePos := geometry.Point{
+		X: rowPos.X + (230 * gs.Max),
+		Y: rowPos.Y,
+	}
+	schemeButton = addControlButton(world, gs, input.SchemeNames[mapping.Scheme], schemePos, buttonSize,
+		schemeChangeEvent{}, false)
+
+	rowPos.Y += controlsRowSize * gs.Max
+
+	// targeting mode
+	addControlLabel(world, gs, "Targeting", rowPos, buttonSize.Height)
+
+	targetingPos := geometry.Point{
+		X: rowPos.X + (230 * gs.Max),
+		Y: rowPos.Y,
+	}
+	targetingButton = addControlButton(world, gs, input.TargetingNames[mapping.Targeting], targetingPos, buttonSize,
+		targetingChangeEvent{}, false)
+
+	rowPos.Y += controlsRowSize * gs.Max
+
+	// dead zone
+	addControlLabel(world, gs, "Dead Zone", rowPos, buttonSize.Height)
+
+	barPos := geometry.Point{
+		X: rowPos.X + (230 * gs.Max),
+		Y: rowPos.Y,
+	}
+
+	barSize := geometry.Size{
+		Width:  (buttonSize.Width * 2) + 20,
+		Height: buttonSize.Height,
+	}
+
+	deadZoneBar = world.AddEntity(
+		ui.ProgressBar{
+			Min:     0,
+			Max:     maxDeadZone,
+			Current: mapping.DeadZone * 100,
+			Shadow: geometry.Size{
+				Width:  2 * gs.Max,
+				Height: 2 * gs.Max,
+			},
+			Sound:  clickSound,
+			Volume: 1,
+			Event:  deadZoneChangeEvent{},
+		},
+		ui.ProgressBarColor{
+			Solid: color.SkyBlue,
+			Gradient: color.Gradient{
+				From:      color.SkyBlue,
+				To:        color.DarkBlue,
+				Direction: color.GradientHorizontal,
+			},
+			Empty:  color.Blue.Blend(color.White, 0.65),
+			Border: color.DarkBlue,
+		},
+		shapes.Box{
+			Size:      barSize,
+			Scale:     gs.Max,
+			Thickness: int32(menuControlBorder * gs.Max),
+		},
+		barPos,
+		menu{name: controlsMenu},
+		effects.Hide{},
+	)
+
+	deadZoneText = world.AddEntity(
+		ui.Text{
+			String:     fmt.Sprintf("%d%%", int(mapping.DeadZone*100)),
+			Size:       fontSmallSize * gs.Max,
+			Font:       font,
+			VAlignment: ui.MiddleVAlignment,
+			HAlignment: ui.CenterHAlignment,
+		},
+		geometry.Point{
+			X: barPos.X + (barSize.Width * 0.5 * gs.Max),
+			Y: barPos.Y + (barSize.Height * 0.5 * gs.Max),
+		},
+		color.White,
+		menu{name: controlsMenu},
+		effects.Hide{},
+	)
+
+	rowPos.Y += (controlsRowSize + 15) * gs.Max
+
+	// defaults and back buttons
+	buttonPos := geometry.Point{
+		X: barPos.X,
+		Y: rowPos.Y,
+	}
+	addControlButton(world, gs, "Defaults", buttonPos, buttonSize, defaultControlsEvent{}, false)
+
+	buttonPos.X += (buttonSize.Width + 20) * gs.Max
+	addControlButton(world, gs, "Back", buttonPos, buttonSize, events.DelaySignal{
+		Signal: changeMenuEvent{name: optionsMenu},
+		Time:   0.25,
+	}, false)
+
+	// listen to control changes
+	world.AddListener(controlsListener, rebindEventType, deadZoneChangeEventType, defaultControlsEventType,
+		schemeChangeEventType, targetingChangeEventType)
+
+	// listen to keys and buttons to rebind
+	world.AddListener(captureListener, events.TYPE.KeyDownEvent, events.TYPE.GamePadButtonDownEvent)
+
+	return nil
+}
+
+// add a label in the controls menu
+func addControlLabel(world *goecs.World, gs geometry.Scale, text string, pos geometry.Point, height float32) {
+	world.AddEntity(
+		ui.Text{
+			String:     text,
+			Size:       fontSmallSize * gs.Max,
+			Font:       font,
+			VAlignment: ui.MiddleVAlignment,
+			HAlignment: ui.LeftHAlignment,
+		},
+		geometry.Point{
+			X: pos.X,
+			Y: pos.Y + (height * 0.5 * gs.Max),
+		},
+		color.White,
+		menu{name: controlsMenu},
+		effects.Hide{},
+	)
+}
+
+// add a button in the controls menu
+func addControlButton(world *goecs.World, gs geometry.Scale, text string, pos geometry.Point, size geometry.Size,
+	event interface{}, focus bool) *goecs.Entity {
+	return world.AddEntity(
+		ui.FlatButton{
+			Shadow: geometry.Size{Width: shadowExtraWidth * gs.Max, Height: shadowExtraHeight * gs.Max},
+			Event:  event,
+			Sound:  clickSound,
+			Volume: 1,
+		},
+		pos,
+		shapes.Box{
+			Size:      size,
+			Scale:     gs.Max,
+			Thickness: int32(menuControlBorder * gs.Max),
+		},
+		ui.Text{
+			String:     text,
+			Size:       fontSmallSize * gs.Max,
+			Font:       font,
+			VAlignment: ui.MiddleVAlignment,
+			HAlignment: ui.CenterHAlignment,
+		},
+		ui.ButtonColor{
+			Gradient: color.Gradient{
+				From: color.Red,
+				To:   color.DarkPurple,
+			},
+			Border: color.DarkBlue,
+			Text:   color.SkyBlue,
+		},
+		menu{name: controlsMenu, focus: focus},
+		effects.Hide{},
+	)
+}
+
+// set the text of a control
+func setText(ent *goecs.Entity, str string) {
+	text := ui.Get.Text(ent)
+	text.String = str
+	ent.Set(text)
+}
+
+// refresh all the buttons with the current mapping
+func refreshControls() {
+	for _, a := range input.Actions {
+		setText(keyButtons[a], input.KeyName(mapping.Bindings[a].Key))
+		setText(padButtons[a], input.ButtonName(mapping.Bindings[a].Button))
+	}
+	setText(schemeButton, input.SchemeNames[mapping.Scheme])
+	setText(targetingButton, input.TargetingNames[mapping.Targeting])
+	bar := ui.Get.ProgressBar(deadZoneBar)
+	bar.Current = mapping.DeadZone * 100
+	deadZoneBar.Set(bar)
+	setText(deadZoneText, fmt.Sprintf("%d%%", int(bar.Current)))
+}
+
+// save the current mapping
+func saveControls(world *goecs.World) {
+	mapping.Save(gEng.GetSettings())
+	world.Signal(input.ReloadEvent{})
+}
+
+func controlsListener(world *goecs.World, signal interface{}, _ float32) error {
+	switch e := signal.(type) {
+	case rebindEvent:
+		// wait for a key or button, without focus so the ui does not use them
+		rb := e
+		rebinding = &rb
+		if e.pad {
+			setText(padButtons[e.action], waitingText)
+		} else {
+			setText(keyButtons[e.action], waitingText)
+		}
+		world.Signal(events.ClearFocusEvent{})
+	case deadZoneChangeEvent:
+		bar := ui.Get.ProgressBar(deadZoneBar)
+		mapping.DeadZone = float32(int(bar.Current)) / 100
+		setText(deadZoneText, fmt.Sprintf("%d%%", int(bar.Current)))
+		saveControls(world)
+	case schemeChangeEvent:
+		if mapping.Scheme == input.Pointer {
+			mapping.Scheme = input.Buttons
+		} else {
+			mapping.Scheme = input.Pointer
+		}
+		setText(schemeButton, input.SchemeNames[mapping.Scheme])
+		saveControls(world)
+	case targetingChangeEvent:
+		mapping.Targeting = mapping.Targeting.Next()
+		setText(targetingButton, input.TargetingNames[mapping.Targeting])
+		saveControls(world)
+	case defaultControlsEvent:
+		mapping = input.Default()
+		refreshControls()
+		saveControls(world)
+	}
+	return nil
+}
+
+// capture a key or a button for the current rebind
+func captureListener(world *goecs.World, signal interface{}, _ float32) error {
+	if rebinding == nil {
+		return nil
+	}
+	switch e := signal.(type) {
+	case events.KeyDownEvent:
+		// the pause key cancel the rebind, on key up
+		if e.Key == mapping.Bindings[input.Pause].Key || rebinding.pad {
+			return nil
+		}
+		mapping.BindKey(rebinding.action, e.Key)
+	case events.GamePadButtonDownEvent:
+		if !rebinding.pad {
+			return nil
+		}
+		mapping.BindButton(rebinding.action, e.Button)
+	}
+	endRebind(world)
+	saveControls(world)
+	return nil
+}
+
+// end the current rebind and focus back in its button
+func endRebind(world *goecs.World) {
+	button := keyButtons[rebinding.action]
+	if rebinding.pad {
+		button = padButtons[rebinding.action]
+	}
+	rebinding = nil
+	refreshControls()
+	world.Signal(events.FocusOnControlEvent{Control: button})
+}
+
+type rebindEvent struct {
+	action input.Action
+	pad    bool
+}
+
+var rebindEventType = reflect.TypeOf(rebindEvent{})
+
+type deadZoneChangeEvent struct{}
+
+var deadZoneChangeEventType = reflect.TypeOf(deadZoneChangeEvent{})
+
+type defaultControlsEvent struct{}
+
+var defaultControlsEventType = reflect.TypeOf(defaultControlsEvent{})
+
+type schemeChangeEvent struct{}
+
+var schemeChangeEventType = reflect.TypeOf(schemeChangeEvent{})
+
+type targetingChangeEvent struct{}
+
+var targetingChangeEventType = reflect.TypeOf(targetingChangeEvent{})
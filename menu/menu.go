@@ -27,7 +27,6 @@ import (
 	"github.com/juan-medina/goecs"
 	"github.com/juan-medina/gosge"
 	"github.com/juan-medina/gosge/components/color"
-	"github.com/juan-medina/gosge/components/device"
 	"github.com/juan-medina/gosge/components/effects"
 	"github.com/juan-medina/gosge/components/geometry"
 	"github.com/juan-medina/gosge/components/shapes"
@@ -35,6 +34,8 @@ import (
 	"github.com/juan-medina/gosge/components/ui"
 	"github.com/juan-medina/gosge/events"
 	"github.com/juan-medina/mesh2prod/game/constants"
+	"github.com/juan-medina/mesh2prod/game/skin"
+	"github.com/juan-medina/mesh2prod/scene"
 	"reflect"
 )
 
@@ -63,6 +64,10 @@ var (
 	barEnt      *goecs.Entity
 	valueLabel  *goecs.Entity
 	currentMenu = mainMenu
+
+	skins             skin.Catalogue // skins catalogue
+	planeSkinButton   *goecs.Entity  // plane skin button
+	payloadSkinButton *goecs.Entity  // payload skin button
 )
 
 // Stage the menu
@@ -114,6 +119,11 @@ func Stage(eng *gosge.Engine) error {
 		return err
 	}
 
+	// create controls menu
+	if err = createControlsMenu(eng, world, dr, gs); err != nil {
+		return err
+	}
+
 	// create play menu
 	if err = createPlayMenu(eng, world, dr, gs); err != nil {
 		return err
@@ -121,6 +131,9 @@ func Stage(eng *gosge.Engine) error {
 
 	world.AddListener(changeMenuListener, changeMenuEventType)
 
+	// add the navigation
+	scene.Navigation(eng, back)
+
 	// set the master volume to it config value
 	currentMaster := eng.GetSettings().GetFloat32(constants.MasterVolumeConfig, constants.DefaultMasterVolume)
 
@@ -166,40 +179,8 @@ func createBackGround(eng *gosge.Engine, world *goecs.World, dr geometry.Size, g
 		return err
 	}
 
-	// add a gradient background
-	world.AddEntity(
-		shapes.SolidBox{
-			Size: geometry.Size{
-				Width:  dr.Width,
-				Height: dr.Height / 2,
-			},
-			Scale: gs.Max,
-		},
-		geometry.Point{},
-		color.Gradient{
-			From:      color.White,
-			To:        color.SkyBlue,
-			Direction: color.GradientVertical,
-		},
-	)
-	// add a gradient background
-	world.AddEntity(
-		shapes.SolidBox{
-			Size: geometry.Size{
-				Width:  dr.Width,
-				Height: dr.Height * 0.5,
-			},
-			Scale: gs.Max,
-		},
-		geometry.Point{
-			Y: dr.Height * 0.5 * gs.Max,
-		},
-		color.Gradient{
-			From:      color.SkyBlue,
-			To:        color.Blue,
-			Direction: color.GradientVertical,
-		},
-	)
+	// add the sky
+	scene.Sky(world, dr, gs)
 
 	// add logo
 	world.AddEntity(
@@ -289,8 +270,11 @@ func createMainMenu(eng *gosge.Engine, world *goecs.World, dr geometry.Size, gs
 		Height: measure.Height * 0.75,
 	}
 
+	// gap between the small buttons
+	smallGap := measure.Width * 0.04
+
 	buttonPos = geometry.Point{
-		X: (dr.Width * gs.Point.X * 0.5) - (measure.Width * 0.5 * gs.Max),
+		X: (dr.Width * gs.Point.X * 0.5) - (((smallSize.Width * 1.5) + smallGap) * gs.Max),
 		Y: (dr.Height * gs.Point.Y) - (measure.Height * gs.Max) - (10 * gs.Max),
 	}
 
@@ -330,7 +314,47 @@ func createMainMenu(eng *gosge.Engine, world *goecs.World, dr geometry.Size, gs
 	)
 
 	buttonPos = geometry.Point{
-		X: (dr.Width * gs.Point.X * 0.5) + (((measure.Width * 0.5) - smallSize.Width) * gs.Max),
+		X: (dr.Width * gs.Point.X * 0.5) - (smallSize.Width * 0.5 * gs.Max),
+		Y: (dr.Height * gs.Point.Y) - (measure.Height * gs.Max) - (10 * gs.Max),
+	}
+
+	// add the shop button, it will sent a event to change to the shop stage
+	world.AddEntity(
+		ui.FlatButton{
+			Shadow: geometry.Size{Width: shadowExtraWidth * gs.Max, Height: shadowExtraHeight * gs.Max},
+			Event: events.DelaySignal{
+				Signal: events.ChangeGameStage{Stage: "shop"},
+				Time:   0.25,
+			},
+			Sound:  clickSound,
+			Volume: 1,
+		},
+		buttonPos,
+		shapes.Box{
+			Size:      smallSize,
+			Scale:     gs.Max,
+			Thickness: int32(menuControlBorder * gs.Max),
+		},
+		ui.Text{
+			String:     "Shop",
+			Size:       fontSmallSize * gs.Max,
+			Font:       font,
+			VAlignment: ui.MiddleVAlignment,
+			HAlignment: ui.CenterHAlignment,
+		},
+		ui.ButtonColor{
+			Gradient: color.Gradient{
+				From: color.Red,
+				To:   color.DarkPurple,
+			},
+			Border: color.DarkBlue,
+			Text:   color.SkyBlue,
+		},
+		menu{name: mainMenu},
+	)
+
+	buttonPos = geometry.Point{
+		X: (dr.Width * gs.Point.X * 0.5) + (((smallSize.Width * 0.5) + smallGap) * gs.Max),
 		Y: (dr.Height * gs.Point.Y) - (measure.Height * gs.Max) - (10 * gs.Max),
 	}
 
@@ -369,20 +393,19 @@ func createMainMenu(eng *gosge.Engine, world *goecs.World, dr geometry.Size, gs
 		menu{name: mainMenu},
 	)
 
-	// listen to keys
-	world.AddListener(menuKeyListener, events.TYPE.KeyUpEvent)
-
-	// listen to gamepad
-	world.AddListener(gamepadListener, events.TYPE.GamePadButtonUpEvent)
-
 	return nil
 }
 
 func createPlayMenu(eng *gosge.Engine, world *goecs.World, dr geometry.Size, gs geometry.Scale) error {
+	var err error
 	cs := constants.CloudSize(eng.GetSettings().GetIn32(constants.CloudSizeConfig, int32(constants.StartupCloud)))
 	panelSize := geometry.Size{
 		Width:  650,
-		Height: 310,
+		Height: 410,
+	}
+
+	if skins, err = skin.Load(constants.SkinsFile); err != nil {
+		return err
 	}
 
 	panelPos := geometry.Point{
@@ -498,6 +521,17 @@ func createPlayMenu(eng *gosge.Engine, world *goecs.World, dr geometry.Size, gs
 		controlPos.X += (controlSize.Width + 10) * gs.Max
 	}
 
+	// add the skins
+	planeSkin, payloadSkin := skins.Selected(eng.GetSettings())
+
+	controlPos.X = panelPos.X + (10 * gs.Max)
+	controlPos.Y += (controlSize.Height + 10) * gs.Max
+	planeSkinButton = addSkinControl(world, gs, "plane", planeSkin.Name, controlPos, changeSkinEvent{payload: false})
+
+	controlPos.Y += (controlSize.Height + 10) * gs.Max
+	payloadSkinButton = addSkinControl(world, gs, "payload", payloadSkin.Name, controlPos,
+		changeSkinEvent{payload: true})
+
 	controlSize = geometry.Size{
 		Width:  200,
 		Height: 70,
@@ -512,7 +546,7 @@ func createPlayMenu(eng *gosge.Engine, world *goecs.World, dr geometry.Size, gs
 	world.AddEntity(
 		ui.FlatButton{
 			Shadow: geometry.Size{Width: shadowExtraWidth * gs.Max, Height: shadowExtraHeight * gs.Max},
-			Event:  events.ChangeGameStage{Stage: "game"},
+			Event:  events.ChangeGameStage{Stage: "campaign"},
 			Sound:  clickSound,
 			Volume: 1,
 		},
@@ -578,6 +612,96 @@ func createPlayMenu(eng *gosge.Engine, world *goecs.World, dr geometry.Size, gs
 	)
 
 	world.AddListener(cloudSizeChangeListener, changeCloudSizeEventType)
+	world.AddListener(skinChangeListener, changeSkinEventType)
+	return nil
+}
+
+// add a label and a button to change a skin in the play menu
+func addSkinControl(world *goecs.World, gs geometry.Scale, label, name string, pos geometry.Point,
+	event changeSkinEvent) *goecs.Entity {
+	controlSize := geometry.Size{
+		Width:  400,
+		Height: 40,
+	}
+
+	world.AddEntity(
+		ui.Text{
+			String:     label,
+			Size:       fontSmallSize * gs.Max,
+			Font:       font,
+			VAlignment: ui.MiddleVAlignment,
+			HAlignment: ui.LeftHAlignment,
+		},
+		geometry.Point{
+			X: pos.X,
+			Y: pos.Y + (controlSize.Height * 0.5 * gs.Max),
+		},
+		color.SkyBlue,
+		menu{name: playMenu},
+		effects.Hide{},
+	)
+
+	return world.AddEntity(
+		ui.FlatButton{
+			Shadow: geometry.Size{Width: shadowExtraWidth * gs.Max, Height: shadowExtraHeight * gs.Max},
+			Event:  event,
+			Sound:  clickSound,
+			Volume: 1,
+		},
+		geometry.Point{
+			X: pos.X + (620-controlSize.Width)*gs.Max,
+			Y: pos.Y,
+		},
+		shapes.Box{
+			Size:      controlSize,
+			Scale:     gs.Max,
+			Thickness: int32(menuControlBorder * gs.Max),
+		},
+		ui.Text{
+			String:     name,
+			Size:       fontSmallSize * gs.Max,
+			Font:       font,
+			VAlignment: ui.MiddleVAlignment,
+			HAlignment: ui.CenterHAlignment,
+		},
+		ui.ButtonColor{
+			Gradient: color.Gradient{
+				From: color.Red,
+				To:   color.DarkPurple,
+			},
+			Border: color.DarkBlue,
+			Text:   color.SkyBlue,
+		},
+		menu{name: playMenu},
+		effects.Hide{},
+	)
+}
+
+// change to the next skin
+func skinChangeListener(_ *goecs.World, signal interface{}, _ float32) error {
+	switch v := signal.(type) {
+	case changeSkinEvent:
+		settings := gEng.GetSettings()
+		planeSkin, payloadSkin := skins.Selected(settings)
+		if v.payload {
+			for i, p := range skins.Payloads {
+				if p.Name == payloadSkin.Name {
+					payloadSkin = skins.Payloads[(i+1)%len(skins.Payloads)]
+					break
+				}
+			}
+		} else {
+			for i, p := range skins.Planes {
+				if p.Name == planeSkin.Name {
+					planeSkin = skins.Planes[(i+1)%len(skins.Planes)]
+					break
+				}
+			}
+		}
+		skin.Select(settings, planeSkin, payloadSkin)
+		setText(planeSkinButton, planeSkin.Name)
+		setText(payloadSkinButton, payloadSkin.Name)
+	}
 	return nil
 }
 
@@ -807,6 +931,52 @@ func createOptionsMenu(eng *gosge.Engine, world *goecs.World, dr geometry.Size,
 		effects.Hide{},
 	)
 
+	controlPos = geometry.Point{
+		X: panelPos.X + (10 * gs.Max),
+		Y: controlPos.Y,
+	}
+
+	controlSize = geometry.Size{
+		Width:  150,
+		Height: 40,
+	}
+
+	// add the controls button
+	world.AddEntity(
+		ui.FlatButton{
+			Shadow: geometry.Size{Width: shadowExtraWidth * gs.Max, Height: shadowExtraHeight * gs.Max},
+			Event: events.DelaySignal{
+				Signal: changeMenuEvent{name: controlsMenu},
+				Time:   0.25,
+			},
+			Sound:  clickSound,
+			Volume: 1,
+		},
+		controlPos,
+		shapes.Box{
+			Size:      controlSize,
+			Scale:     gs.Max,
+			Thickness: int32(menuControlBorder * gs.Max),
+		},
+		ui.Text{
+			String:     "Controls",
+			Size:       fontSmallSize * gs.Max,
+			Font:       font,
+			VAlignment: ui.MiddleVAlignment,
+			HAlignment: ui.CenterHAlignment,
+		},
+		ui.ButtonColor{
+			Gradient: color.Gradient{
+				From: color.Red,
+				To:   color.DarkPurple,
+			},
+			Border: color.DarkBlue,
+			Text:   color.SkyBlue,
+		},
+		menu{name: optionsMenu},
+		effects.Hide{},
+	)
+
 	// listen to option changes
 	world.AddListener(optionsListener, masterVolumeChangeEventType, cancelOptionsEventType, saveOptionsEventType)
 
@@ -851,38 +1021,22 @@ func optionsListener(world *goecs.World, signal interface{}, _ float32) error {
 	return nil
 }
 
-func menuKeyListener(world *goecs.World, signal interface{}, _ float32) error {
-	switch e := signal.(type) {
-	case events.KeyUpEvent:
-		if e.Key == device.KeyEscape {
-			switch currentMenu {
-			case mainMenu:
-				world.Signal(events.GameCloseEvent{})
-			case optionsMenu:
-				world.Signal(cancelOptionsEvent{})
-			case playMenu:
-				world.Signal(changeMenuEvent{name: mainMenu})
-			}
-		}
-	}
-	return nil
-}
-
-func gamepadListener(world *goecs.World, signal interface{}, _ float32) error {
-	switch e := signal.(type) {
-	case events.GamePadButtonUpEvent:
-		key := device.FirstKey
-		if e.Button == device.GamepadStart {
-			key = device.KeyReturn
-		} else if e.Button == device.GamepadSelect || e.Button == device.GamepadButton2 {
-			key = device.KeyEscape
-		}
-		if key != device.FirstKey {
-			world.Signal(events.KeyDownEvent{Key: key})
-			world.Signal(events.KeyUpEvent{Key: key})
+// go back from the current menu
+func back(world *goecs.World) {
+	switch currentMenu {
+	case mainMenu:
+		world.Signal(events.GameCloseEvent{})
+	case optionsMenu:
+		world.Signal(cancelOptionsEvent{})
+	case controlsMenu:
+		if rebinding != nil {
+			endRebind(world)
+		} else {
+			world.Signal(changeMenuEvent{name: optionsMenu})
 		}
+	case playMenu:
+		world.Signal(changeMenuEvent{name: mainMenu})
 	}
-	return nil
 }
 
 type masterVolumeChangeEvent struct{}
@@ -915,3 +1069,9 @@ type changeMenuEvent struct {
 }
 
 var changeMenuEventType = reflect.TypeOf(changeMenuEvent{})
+
+type changeSkinEvent struct {
+	payload bool // change the payload skin, otherwise the plane skin
+}
+
+var changeSkinEventType = reflect.TypeOf(changeSkinEvent{})
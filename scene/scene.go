@@ -0,0 +1,185 @@
+/*
+ * Copyright (c) 2020 Juan Medina.
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package scene
+
+import (
+	"github.com/juan-medina/goecs"
+	"github.com/juan-medina/gosge"
+	"github.com/juan-medina/gosge/components/color"
+	"github.com/juan-medina/gosge/components/device"
+	"github.com/juan-medina/gosge/components/geometry"
+	"github.com/juan-medina/gosge/components/shapes"
+	"github.com/juan-medina/gosge/events"
+	"github.com/juan-medina/mesh2prod/game/daytime"
+	"github.com/juan-medina/mesh2prod/game/input"
+	"github.com/juan-medina/mesh2prod/game/theme"
+)
+
+const (
+	skyCycle = 60 // seconds that the sky takes to go from dawn to night and back
+)
+
+var (
+	skyTop    *goecs.Entity // top sky gradient
+	skyBottom *goecs.Entity // bottom sky gradient
+	skyTime   float32       // time of the sky cycle
+
+	topSky    = theme.Sky{From: color.White, To: color.SkyBlue} // top sky, at day
+	bottomSky = theme.Sky{From: color.SkyBlue, To: color.Blue}  // bottom sky, at day
+)
+
+// Sky adds the sky of the menus, that goes thru the day
+func Sky(world *goecs.World, dr geometry.Size, gs geometry.Scale) {
+	// add a gradient background
+	skyTop = world.AddEntity(
+		shapes.SolidBox{
+			Size: geometry.Size{
+				Width:  dr.Width,
+				Height: dr.Height / 2,
+			},
+			Scale: gs.Max,
+		},
+		geometry.Point{},
+		topSky.Gradient(),
+	)
+	// add a gradient background
+	skyBottom = world.AddEntity(
+		shapes.SolidBox{
+			Size: geometry.Size{
+				Width:  dr.Width,
+				Height: dr.Height * 0.5,
+			},
+			Scale: gs.Max,
+		},
+		geometry.Point{
+			Y: dr.Height * 0.5 * gs.Max,
+		},
+		bottomSky.Gradient(),
+	)
+
+	// the sky goes thru the day
+	skyTime = 0
+	world.AddSystem(skySystem)
+}
+
+// change the sky with the time of day
+func skySystem(_ *goecs.World, delta float32) error {
+	skyTime += delta
+	look := daytime.At(daytime.Cycle(skyTime, skyCycle))
+	skyTop.Set(look.Sky(topSky).Gradient())
+	skyBottom.Set(look.Sky(bottomSky).Gradient())
+	return nil
+}
+
+// BackFunc is called when the player wants to go back
+type BackFunc func(world *goecs.World)
+
+// navigation of a stage, with the bound actions
+type navigation struct {
+	eng     *gosge.Engine
+	back    BackFunc                              // back function of the stage
+	mapping input.Mapping                         // current input mapping
+	keys    map[device.Key]input.Action           // action of each pressed key, when it was pressed
+	buttons map[device.GamepadButton]input.Action // action of each pressed button, when it was pressed
+}
+
+// Navigation adds the bound actions navigation to a stage, Pause goes back with the BackFunc,
+// and Fire activates the focused control
+func Navigation(eng *gosge.Engine, back BackFunc) {
+	nav := navigation{
+		eng:     eng,
+		back:    back,
+		mapping: input.Load(eng.GetSettings()),
+		keys:    make(map[device.Key]input.Action),
+		buttons: make(map[device.GamepadButton]input.Action),
+	}
+
+	world := eng.World()
+
+	// listen to keys
+	world.AddListener(nav.keyListener, events.TYPE.KeyDownEvent, events.TYPE.KeyUpEvent)
+
+	// listen to gamepad
+	world.AddListener(nav.gamepadListener, events.TYPE.GamePadButtonDownEvent, events.TYPE.GamePadButtonUpEvent)
+
+	// listen to reloads
+	world.AddListener(nav.reloadListener, input.ReloadEventType)
+}
+
+// translate keys into actions, when they are released
+func (nav *navigation) keyListener(world *goecs.World, signal interface{}, _ float32) error {
+	switch e := signal.(type) {
+	case events.KeyDownEvent:
+		if a, ok := nav.mapping.KeyAction(e.Key); ok {
+			nav.keys[e.Key] = a
+		}
+	case events.KeyUpEvent:
+		pressed, ok := nav.keys[e.Key]
+		delete(nav.keys, e.Key)
+		// the key should still be bound to the action that has been pressed
+		if a, bound := nav.mapping.KeyAction(e.Key); ok && bound && a == pressed {
+			nav.action(world, a, e.Key == device.KeySpace || e.Key == device.KeyReturn)
+		}
+	}
+	return nil
+}
+
+// translate gamepad buttons into actions, when they are released
+func (nav *navigation) gamepadListener(world *goecs.World, signal interface{}, _ float32) error {
+	switch e := signal.(type) {
+	case events.GamePadButtonDownEvent:
+		if a, ok := nav.mapping.ButtonAction(e.Button); ok {
+			nav.buttons[e.Button] = a
+		}
+	case events.GamePadButtonUpEvent:
+		pressed, ok := nav.buttons[e.Button]
+		delete(nav.buttons, e.Button)
+		// the button should still be bound to the action that has been pressed
+		if a, bound := nav.mapping.ButtonAction(e.Button); ok && bound && a == pressed {
+			nav.action(world, a, e.Button == device.GamepadButton3)
+		}
+	}
+	return nil
+}
+
+// do an action, native actions are already handled by the ui
+func (nav navigation) action(world *goecs.World, action input.Action, native bool) {
+	switch action {
+	case input.Pause:
+		nav.back(world)
+	case input.Fire:
+		if !native {
+			world.Signal(events.KeyDownEvent{Key: device.KeyReturn})
+			world.Signal(events.KeyUpEvent{Key: device.KeyReturn})
+		}
+	}
+}
+
+// reload the mapping
+func (nav *navigation) reloadListener(_ *goecs.World, signal interface{}, _ float32) error {
+	switch signal.(type) {
+	case input.ReloadEvent:
+		nav.mapping = input.Load(nav.eng.GetSettings())
+	}
+	return nil
+}
@@ -0,0 +1,343 @@
+/*
+ * Copyright (c) 2020 Juan Medina.
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package shop
+
+import (
+	"fmt"
+	"github.com/juan-medina/goecs"
+	"github.com/juan-medina/gosge"
+	"github.com/juan-medina/gosge/components/color"
+	"github.com/juan-medina/gosge/components/geometry"
+	"github.com/juan-medina/gosge/components/shapes"
+	"github.com/juan-medina/gosge/components/ui"
+	"github.com/juan-medina/gosge/events"
+	"github.com/juan-medina/mesh2prod/game/upgrade"
+	"github.com/juan-medina/mesh2prod/scene"
+	"reflect"
+)
+
+const (
+	clickSound        = "resources/audio/click.wav"      // button click sound
+	buySound          = "resources/audio/win.wav"        // upgrade bought sound
+	shadowExtraWidth  = 3                                // the x offset for the buttons shadow
+	shadowExtraHeight = 3                                // the y offset for the buttons shadow
+	font              = "resources/fonts/go_regular.fnt" // our message text font
+	fontBigSize       = 60                               // big text font size
+	fontSmallSize     = 30                               // small text font size
+	controlBorder     = 2                                // controls border thickness
+	rowWidth          = 1000                             // width of an upgrade row
+	rowHeight         = 90                               // height of an upgrade row
+	buyWidth          = 250                              // width of the buy buttons
+	buyHeight         = 60                               // height of the buy buttons
+	music             = "resources/music/menu/Of Far Different Nature - Adventure Begins (CC-BY).ogg"
+)
+
+// the entities of an upgrade row
+type row struct {
+	level *goecs.Entity // level text
+	buy   *goecs.Entity // buy button
+}
+
+var (
+	gEng       *gosge.Engine
+	walletText *goecs.Entity
+	rows       map[upgrade.Upgrade]row
+)
+
+// Stage the upgrades shop
+func Stage(eng *gosge.Engine) error {
+	var err error
+	gEng = eng
+	eng.DisableExitKey()
+
+	// preload music
+	if err = eng.LoadMusic(music); err != nil {
+		return err
+	}
+
+	// pre-load click sound
+	if err = eng.LoadSound(clickSound); err != nil {
+		return err
+	}
+
+	// pre-load buy sound
+	if err = eng.LoadSound(buySound); err != nil {
+		return err
+	}
+
+	// pre-load font
+	if err = eng.LoadFont(font); err != nil {
+		return err
+	}
+
+	// design resolution is how our game is designed
+	dr := geometry.Size{Width: 1920, Height: 1080}
+
+	// game scale from the real screen size to our design resolution
+	gs := eng.GetScreenSize().CalculateScale(dr)
+
+	// get the ECS world
+	world := eng.World()
+
+	// add the sky
+	scene.Sky(world, dr, gs)
+
+	// create the shop
+	createShop(world, dr, gs)
+
+	// update the rows from the settings
+	updateRows()
+
+	// listen to buys
+	world.AddListener(buyListener, buyEventType)
+
+	// add the navigation
+	scene.Navigation(eng, back)
+
+	world.Signal(events.PlayMusicEvent{Name: music, Volume: 0.5})
+
+	return nil
+}
+
+func createShop(world *goecs.World, dr geometry.Size, gs geometry.Scale) {
+	// add the title
+	world.AddEntity(
+		ui.Text{
+			String:     "Upgrades",
+			Size:       fontBigSize * gs.Max,
+			Font:       font,
+			VAlignment: ui.MiddleVAlignment,
+			HAlignment: ui.CenterHAlignment,
+		},
+		geometry.Point{
+			X: dr.Width * gs.Point.X * 0.5,
+			Y: dr.Height * gs.Point.Y * 0.15,
+		},
+		color.White,
+	)
+
+	// add the wallet
+	walletText = world.AddEntity(
+		ui.Text{
+			Size:       fontSmallSize * gs.Max,
+			Font:       font,
+			VAlignment: ui.MiddleVAlignment,
+			HAlignment: ui.CenterHAlignment,
+		},
+		geometry.Point{
+			X: dr.Width * gs.Point.X * 0.5,
+			Y: (dr.Height * gs.Point.Y * 0.15) + (fontBigSize * gs.Max),
+		},
+		color.Yellow,
+	)
+
+	rowPos := geometry.Point{
+		X: (dr.Width * gs.Point.X * 0.5) - (rowWidth * 0.5 * gs.Max),
+		Y: dr.Height * gs.Point.Y * 0.3,
+	}
+
+	rows = make(map[upgrade.Upgrade]row)
+
+	var focus *goecs.Entity
+
+	for _, u := range upgrade.Upgrades {
+		rows[u] = addRow(world, gs, u, rowPos)
+		if focus == nil {
+			focus = rows[u].buy
+		}
+		rowPos.Y += rowHeight * gs.Max
+	}
+
+	backSize := geometry.Size{
+		Width:  200,
+		Height: 50,
+	}
+
+	// add the back button
+	world.AddEntity(
+		ui.FlatButton{
+			Shadow: geometry.Size{Width: shadowExtraWidth * gs.Max, Height: shadowExtraHeight * gs.Max},
+			Event: events.DelaySignal{
+				Signal: events.ChangeGameStage{Stage: "menu"},
+				Time:   0.25,
+			},
+			Sound:  clickSound,
+			Volume: 1,
+		},
+		geometry.Point{
+			X: (dr.Width * gs.Point.X * 0.5) - (backSize.Width * 0.5 * gs.Max),
+			Y: (dr.Height * gs.Point.Y * 0.8),
+		},
+		shapes.Box{
+			Size:      backSize,
+			Scale:     gs.Max,
+			Thickness: int32(controlBorder * gs.Max),
+		},
+		ui.Text{
+			String:     "Back",
+			Size:       fontSmallSize * gs.Max,
+			Font:       font,
+			VAlignment: ui.MiddleVAlignment,
+			HAlignment: ui.CenterHAlignment,
+		},
+		ui.ButtonColor{
+			Gradient: color.Gradient{
+				From: color.Red,
+				To:   color.DarkPurple,
+			},
+			Border: color.DarkBlue,
+			Text:   color.SkyBlue,
+		},
+	)
+
+	world.Signal(events.FocusOnControlEvent{Control: focus})
+}
+
+// add a row with the upgrade name, level and buy button
+func addRow(world *goecs.World, gs geometry.Scale, u upgrade.Upgrade, pos geometry.Point) row {
+	middleY := pos.Y + (buyHeight * 0.5 * gs.Max)
+
+	world.AddEntity(
+		ui.Text{
+			String:     u.Name(),
+			Size:       fontSmallSize * gs.Max,
+			Font:       font,
+			VAlignment: ui.MiddleVAlignment,
+			HAlignment: ui.LeftHAlignment,
+		},
+		geometry.Point{
+			X: pos.X,
+			Y: middleY,
+		},
+		color.White,
+	)
+
+	level := world.AddEntity(
+		ui.Text{
+			Size:       fontSmallSize * gs.Max,
+			Font:       font,
+			VAlignment: ui.MiddleVAlignment,
+			HAlignment: ui.CenterHAlignment,
+		},
+		geometry.Point{
+			X: pos.X + (rowWidth * 0.55 * gs.Max),
+			Y: middleY,
+		},
+		color.White,
+	)
+
+	buy := world.AddEntity(
+		ui.FlatButton{
+			Shadow: geometry.Size{Width: shadowExtraWidth * gs.Max, Height: shadowExtraHeight * gs.Max},
+			Event:  buyEvent{upgrade: u},
+			Sound:  clickSound,
+			Volume: 1,
+		},
+		geometry.Point{
+			X: pos.X + ((rowWidth - buyWidth) * gs.Max),
+			Y: pos.Y,
+		},
+		shapes.Box{
+			Size: geometry.Size{
+				Width:  buyWidth,
+				Height: buyHeight,
+			},
+			Scale:     gs.Max,
+			Thickness: int32(controlBorder * gs.Max),
+		},
+		ui.Text{
+			Size:       fontSmallSize * gs.Max,
+			Font:       font,
+			VAlignment: ui.MiddleVAlignment,
+			HAlignment: ui.CenterHAlignment,
+		},
+		ui.ButtonColor{
+			Gradient: color.Gradient{
+				From: color.Red,
+				To:   color.DarkPurple,
+			},
+			Border: color.DarkBlue,
+			Text:   color.SkyBlue,
+		},
+	)
+
+	return row{level: level, buy: buy}
+}
+
+// update the wallet and the rows from the settings
+func updateRows() {
+	settings := gEng.GetSettings()
+	wallet := upgrade.Wallet(settings)
+
+	text := ui.Get.Text(walletText)
+	text.String = fmt.Sprintf("Wallet: %d BlockCoins", wallet)
+	walletText.Set(text)
+
+	for u, r := range rows {
+		text = ui.Get.Text(r.level)
+		text.String = fmt.Sprintf("Level %d/%d", u.Level(settings), u.Levels())
+		r.level.Set(text)
+
+		text = ui.Get.Text(r.buy)
+		bc := ui.Get.ButtonColor(r.buy)
+		bc.Text = color.SkyBlue
+		if cost, ok := u.Cost(settings); ok {
+			text.String = fmt.Sprintf("Buy %d", cost)
+			// we could not afford it
+			if cost > wallet {
+				bc.Text = color.Gray
+			}
+		} else {
+			text.String = "Max"
+			bc.Text = color.Gray
+		}
+		r.buy.Set(text)
+		r.buy.Set(bc)
+	}
+}
+
+func buyListener(world *goecs.World, signal interface{}, _ float32) error {
+	switch e := signal.(type) {
+	case buyEvent:
+		if e.upgrade.Buy(gEng.GetSettings()) {
+			world.Signal(events.PlaySoundEvent{Name: buySound, Volume: 0.5})
+			updateRows()
+		}
+	}
+	return nil
+}
+
+// go back to the menu
+func back(world *goecs.World) {
+	world.Signal(events.PlaySoundEvent{Name: clickSound, Volume: 1})
+	world.Signal(events.DelaySignal{
+		Signal: events.ChangeGameStage{Stage: "menu"},
+		Time:   0.25,
+	})
+}
+
+type buyEvent struct {
+	upgrade upgrade.Upgrade
+}
+
+var buyEventType = reflect.TypeOf(buyEvent{})